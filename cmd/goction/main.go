@@ -1,88 +1,118 @@
 package main
 
 import (
+	"flag"
 	"fmt"
-	"io"
 	"os"
-	"path/filepath"
+	"strings"
 
 	"goction/internal/api"
 	"goction/internal/cmd"
 	"goction/internal/config"
+	"goction/internal/logging"
+	"goction/internal/runner"
 	"goction/internal/stats"
 
 	"github.com/sirupsen/logrus"
 )
 
 func main() {
+	// Goction child processes only execute a goction and report to the parent runner
+	if len(os.Args) > 1 && os.Args[1] == runner.ChildCommand {
+		os.Exit(runner.ChildMain())
+	}
+
 	fmt.Println("Starting Goction...")
 
+	// Parse global flags, which precede the command
+	opts, cmdArgs, err := parseGlobalFlags(os.Args[1:])
+	if err != nil {
+		fmt.Printf("Error parsing flags: %v\n", err)
+		os.Exit(1)
+	}
+
+	// The config command must work with an invalid configuration to inspect and fix it
+	opts.SkipValidation = len(cmdArgs) > 0 && cmdArgs[0] == "config"
+
 	// Load configuration
-	cfg, err := config.Load()
+	cfg, err := config.Load(opts)
 	if err != nil {
 		fmt.Printf("Error loading configuration: %v\n", err)
 		os.Exit(1)
 	}
 
-	// Initialize logger
-	logger, err := initializeLogger(cfg)
+	// Initialize the logger shared by the commands and the server
+	logger, err := logging.New(cfg)
 	if err != nil {
-		fmt.Printf("Error initializing logger: %v\n", err)
-		os.Exit(1)
+		if !opts.SkipValidation {
+			fmt.Printf("Error initializing logger: %v\n", err)
+			os.Exit(1)
+		}
+		// Keep the config command usable to fix the logging configuration
+		logger = logrus.New()
+		logger.SetOutput(os.Stderr)
+		logger.WithError(err).Warn("Invalid logging configuration, logging to stderr")
 	}
 
 	// Initialize stats manager
 	statsManager, err := stats.NewManager(cfg.StatsFile)
 	if err != nil {
-		logger.Fatalf("Failed to create stats manager: %v", err)
+		logger.WithError(err).Error("Failed to create stats manager")
+		fmt.Fprintf(os.Stderr, "Failed to create stats manager: %v\n", err)
+		os.Exit(1)
 	}
 
 	// Check command-line arguments
-	if len(os.Args) < 2 {
-		fmt.Println("Usage: goction [new|start|stop|serve|list|update|token|stats|dashboard|run|export|import|config|logs|self-update]")
+	if len(cmdArgs) < 1 {
+		fmt.Println("Usage: goction [--config <path>] [--set key=value ...] [new|start|stop|serve|list|update|remove|token|stats|history|dashboard|run|export|import|config|secret|logs|self-update]")
 		os.Exit(1)
 	}
 
 	// Execute the appropriate command
-	command := os.Args[1]
-	args := os.Args[2:]
+	command := cmdArgs[0]
+	args := cmdArgs[1:]
 
-	logger.Infof("Executing command: %s", command)
+	logger.WithField("command", command).Info("Executing command")
 
 	err = executeCommand(command, args, cfg, statsManager, logger)
 
 	if err != nil {
-		logger.Fatalf("Error executing command: %v", err)
+		logger.WithError(err).WithField("command", command).Error("Command failed")
+		fmt.Fprintf(os.Stderr, "Error executing command: %v\n", err)
+		os.Exit(1)
 	}
 
 	fmt.Println("Goction execution completed.")
 }
 
-func initializeLogger(cfg *config.Config) (*logrus.Logger, error) {
-	logger := logrus.New()
+// overrides collects repeated --set key=value flags
+type overrides map[string]string
 
-	// Ensure log directory exists
-	logDir := filepath.Dir(cfg.LogFile)
-	if err := os.MkdirAll(logDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create log directory: %w", err)
-	}
+func (o overrides) String() string {
+	return fmt.Sprint(map[string]string(o))
+}
 
-	// Set up file logging
-	file, err := os.OpenFile(cfg.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open log file: %w", err)
+func (o overrides) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("expected key=value, got %q", value)
 	}
+	o[key] = val
+	return nil
+}
 
-	// Use multi-writer to log to both file and console
-	mw := io.MultiWriter(os.Stdout, file)
-	logger.SetOutput(mw)
+// parseGlobalFlags parses the flags given before the command and returns the remaining arguments
+func parseGlobalFlags(args []string) (config.LoadOptions, []string, error) {
+	opts := config.LoadOptions{Overrides: make(overrides)}
 
-	logger.SetLevel(logrus.DebugLevel)
-	logger.SetFormatter(&logrus.TextFormatter{
-		FullTimestamp: true,
-	})
+	fs := flag.NewFlagSet("goction", flag.ContinueOnError)
+	fs.StringVar(&opts.Path, "config", "", "configuration file (default $"+config.PathEnv+" or "+config.DefaultPath()+")")
+	fs.Var(overrides(opts.Overrides), "set", "override a configuration value, as key=value (repeatable)")
+	if err := fs.Parse(args); err != nil {
+		return opts, nil, err
+	}
 
-	return logger, nil
+	return opts, fs.Args(), nil
 }
 
 func executeCommand(command string, args []string, cfg *config.Config, statsManager *stats.Manager, logger *logrus.Logger) error {
@@ -99,31 +129,43 @@ func executeCommand(command string, args []string, cfg *config.Config, statsMana
 		return cmd.ListGoctions(cfg)
 	case "update":
 		return cmd.UpdateGoction(args, cfg)
+	case "remove":
+		return cmd.RemoveGoction(args, cfg, statsManager)
 	case "token":
 		return cmd.ShowToken(cfg)
 	case "stats":
-		return cmd.ShowStats(args, statsManager)
+		return cmd.ShowStats(args, cfg, statsManager)
+	case "history":
+		return cmd.ShowHistory(args, statsManager)
 	case "dashboard":
 		return cmd.ShowDashboard(cfg)
 	case "run":
 		if len(args) < 1 {
 			return fmt.Errorf("Usage: goction run <goction-name> [arg1 arg2 ...]")
 		}
-		return cmd.RunGoction(args[0], args[1:], cfg)
+		return cmd.RunGoction(args[0], args[1:], cfg, statsManager, logger)
 	case "config":
 		if len(args) == 0 {
-			return fmt.Errorf("Usage: goction config [view|reset]")
+			return fmt.Errorf("Usage: goction config [view|get|set|validate|reset]")
 		}
 		switch args[0] {
 		case "view":
 			return cmd.ConfigView(cfg)
+		case "get":
+			return cmd.ConfigGet(args[1:], cfg)
+		case "set":
+			return cmd.ConfigSet(args[1:], cfg)
+		case "validate":
+			return cmd.ConfigValidate(cfg)
 		case "reset":
 			return cmd.ConfigReset(cfg)
 		default:
 			return fmt.Errorf("Unknown config subcommand: %s", args[0])
 		}
+	case "secret":
+		return cmd.ManageSecrets(args, cfg)
 	case "logs":
-		return cmd.ShowLogs(cfg)
+		return cmd.ShowLogs(args, cfg)
 	case "self-update":
 		return cmd.SelfUpdate()
 	case "export":
@@ -136,8 +178,11 @@ func executeCommand(command string, args []string, cfg *config.Config, statsMana
 }
 
 func serveAPI(cfg *config.Config, logger *logrus.Logger) error {
+	if err := cfg.ValidateServer(); err != nil {
+		return err
+	}
 	fmt.Println("Initializing server...")
-	server, err := api.NewServer(cfg)
+	server, err := api.NewServer(cfg, logger)
 	if err != nil {
 		return fmt.Errorf("Failed to create server: %v", err)
 	}
@@ -161,9 +161,14 @@ initialize_config() {
   "api_token": "$(uuidgen)",
   "stats_file": "/var/log/goction/goction_stats.json",
   "dashboard_username": "admin",
-  "dashboard_password": "$(uuidgen)"
+  "dashboard_password": "$(uuidgen)",
+  "session_key": "$(head -c 32 /dev/urandom | od -An -tx1 | tr -d ' \n')",
+  "execution_mode": "process"
 }
 EOF
+    elif ! grep -q '"session_key"' "$CONFIG_FILE"; then
+        print_warning "$CONFIG_FILE has no session_key, which goction serve requires. Generate one with:"
+        print_warning "  goction config set session_key \"\$(openssl rand -hex 32)\""
     fi
     
     log_message "Configuration initialized"
@@ -294,7 +299,10 @@ After=network.target
 
 [Service]
 ExecStart=/usr/local/bin/goction serve
+ExecReload=/bin/kill -HUP \$MAINPID
 Restart=on-failure
+# Lets goction create cgroups enforcing the memory and process limits of goctions
+Delegate=memory pids
 User=$GOCTION_USER
 Group=$GOCTION_GROUP
 WorkingDirectory=/etc/goction
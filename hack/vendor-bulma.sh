@@ -0,0 +1,20 @@
+#!/bin/bash
+
+# Vendors Bulma, unchanged, into the embedded dashboard assets:
+# css/bulma.min.css and its license are copied from the npm package of the given version.
+
+set -e
+
+BULMA_VERSION="${1:-0.9.3}"
+ASSETS_DIR="$(cd "$(dirname "$0")/.." && pwd)/internal/api/dashboard/assets/static"
+
+TMP_DIR="$(mktemp -d)"
+trap 'rm -rf "$TMP_DIR"' EXIT
+
+echo "Downloading Bulma ${BULMA_VERSION}..."
+curl -fsSL "https://registry.npmjs.org/bulma/-/bulma-${BULMA_VERSION}.tgz" -o "$TMP_DIR/bulma.tgz"
+tar -xzf "$TMP_DIR/bulma.tgz" -C "$TMP_DIR" package/css/bulma.min.css package/LICENSE
+
+cp "$TMP_DIR/package/css/bulma.min.css" "$ASSETS_DIR/bulma.min.css"
+cp "$TMP_DIR/package/LICENSE" "$ASSETS_DIR/bulma.LICENSE"
+echo "Bulma ${BULMA_VERSION} vendored into $ASSETS_DIR"
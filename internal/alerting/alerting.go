@@ -0,0 +1,415 @@
+package alerting
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"goction/internal/config"
+	"goction/internal/logging"
+	"goction/internal/runner"
+	"goction/internal/stats"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Rule types
+const (
+	RuleErrorRate = "error_rate"
+	RuleLatency   = "latency"
+	RuleNoSuccess = "no_success"
+)
+
+// Alert states
+const (
+	StatePending  = "pending"
+	StateFiring   = "firing"
+	StateResolved = "resolved"
+)
+
+// DefaultEvaluationInterval is used when the configuration does not set one
+const DefaultEvaluationInterval = time.Minute
+
+// ResolvedRetention is how long resolved alerts are kept before they are forgotten
+const ResolvedRetention = 24 * time.Hour
+
+// Alert is the state of a rule for one goction
+type Alert struct {
+	Rule       string     `json:"rule"`
+	Type       string     `json:"type"`
+	Goction    string     `json:"goction"`
+	State      string     `json:"state"`
+	Value      float64    `json:"value"`
+	Threshold  float64    `json:"threshold"`
+	Message    string     `json:"message"`
+	ActiveAt   *time.Time `json:"active_at,omitempty"`
+	FiredAt    *time.Time `json:"fired_at,omitempty"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+}
+
+// rule is a validated config.AlertRule
+type rule struct {
+	config.AlertRule
+	window     time.Duration
+	pendingFor time.Duration
+}
+
+// ruleSet is a validated alerting configuration, replaced as a whole when the configuration is reloaded
+type ruleSet struct {
+	config   *config.Config
+	rules    []rule
+	channels map[string]config.AlertChannel
+	interval time.Duration
+}
+
+// Manager periodically evaluates alerting rules against execution statistics and sends notifications
+type Manager struct {
+	stats  *stats.Manager
+	runner *runner.Runner
+	logger *logrus.Logger
+	set    atomic.Pointer[ruleSet]
+
+	mu     sync.RWMutex
+	alerts map[string]*Alert
+}
+
+// NewManager validates the alerting configuration and creates a manager.
+// The runner is used by goction channels and may be nil if none is configured.
+func NewManager(cfg *config.Config, statsManager *stats.Manager, goctionRunner *runner.Runner, logger *logrus.Logger) (*Manager, error) {
+	set, err := newRuleSet(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manager{
+		stats:  statsManager,
+		runner: goctionRunner,
+		logger: logger,
+		alerts: make(map[string]*Alert),
+	}
+	m.set.Store(set)
+	return m, nil
+}
+
+// Reload validates a new alerting configuration and applies it from the next evaluation.
+// Alerts of rules that no longer exist are dropped; the others keep their state.
+func (m *Manager) Reload(cfg *config.Config) error {
+	set, err := newRuleSet(cfg)
+	if err != nil {
+		return err
+	}
+	m.set.Store(set)
+
+	names := make(map[string]bool)
+	for _, r := range set.rules {
+		names[r.Name] = true
+	}
+	m.mu.Lock()
+	for key, alert := range m.alerts {
+		if !names[alert.Rule] {
+			delete(m.alerts, key)
+		}
+	}
+	m.mu.Unlock()
+	return nil
+}
+
+func newRuleSet(cfg *config.Config) (*ruleSet, error) {
+	set := &ruleSet{
+		config:   cfg,
+		channels: make(map[string]config.AlertChannel),
+		interval: DefaultEvaluationInterval,
+	}
+
+	if cfg.Alerts.EvaluationInterval != "" {
+		interval, err := time.ParseDuration(cfg.Alerts.EvaluationInterval)
+		if err != nil || interval <= 0 {
+			return nil, fmt.Errorf("invalid alerts evaluation_interval %q", cfg.Alerts.EvaluationInterval)
+		}
+		set.interval = interval
+	}
+
+	for _, channel := range cfg.Alerts.Channels {
+		if err := validateChannel(channel, cfg); err != nil {
+			return nil, err
+		}
+		set.channels[channel.Name] = channel
+	}
+
+	names := make(map[string]bool)
+	for _, r := range cfg.Alerts.Rules {
+		validated, err := set.validateRule(r)
+		if err != nil {
+			return nil, err
+		}
+		if names[r.Name] {
+			return nil, fmt.Errorf("duplicate alert rule name %q", r.Name)
+		}
+		names[r.Name] = true
+		set.rules = append(set.rules, validated)
+	}
+
+	return set, nil
+}
+
+func (set *ruleSet) validateRule(r config.AlertRule) (rule, error) {
+	if r.Name == "" {
+		return rule{}, fmt.Errorf("alert rule without a name")
+	}
+
+	validated := rule{AlertRule: r}
+	var err error
+	if validated.window, err = time.ParseDuration(r.Window); err != nil || validated.window <= 0 {
+		return rule{}, fmt.Errorf("alert rule %q: invalid window %q", r.Name, r.Window)
+	}
+	if r.For != "" {
+		if validated.pendingFor, err = time.ParseDuration(r.For); err != nil || validated.pendingFor < 0 {
+			return rule{}, fmt.Errorf("alert rule %q: invalid for %q", r.Name, r.For)
+		}
+	}
+
+	switch r.Type {
+	case RuleErrorRate:
+		if r.Threshold <= 0 || r.Threshold > 100 {
+			return rule{}, fmt.Errorf("alert rule %q: error_rate threshold must be a percentage between 0 and 100", r.Name)
+		}
+	case RuleLatency:
+		if r.Threshold <= 0 {
+			return rule{}, fmt.Errorf("alert rule %q: latency threshold must be a positive number of seconds", r.Name)
+		}
+		if validated.Percentile == 0 {
+			validated.Percentile = 95
+		}
+		if validated.Percentile < 0 || validated.Percentile > 100 {
+			return rule{}, fmt.Errorf("alert rule %q: percentile must be between 0 and 100", r.Name)
+		}
+	case RuleNoSuccess:
+	default:
+		return rule{}, fmt.Errorf("alert rule %q: unknown type %q (expected %s, %s or %s)", r.Name, r.Type, RuleErrorRate, RuleLatency, RuleNoSuccess)
+	}
+
+	if validated.MinCalls <= 0 {
+		validated.MinCalls = 1
+	}
+
+	for _, name := range r.Channels {
+		if _, ok := set.channels[name]; !ok {
+			return rule{}, fmt.Errorf("alert rule %q: unknown channel %q", r.Name, name)
+		}
+	}
+
+	return validated, nil
+}
+
+// Run evaluates the rules every evaluation interval until stop is closed.
+// A reload changing the interval takes effect after the next evaluation.
+func (m *Manager) Run(stop <-chan struct{}) {
+	interval := m.set.Load().interval
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	m.Evaluate(time.Now())
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			m.Evaluate(now)
+			if next := m.set.Load().interval; next != interval {
+				interval = next
+				ticker.Reset(interval)
+			}
+		}
+	}
+}
+
+// Alerts returns the pending, firing and resolved alerts, firing first
+func (m *Manager) Alerts() []Alert {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	alerts := make([]Alert, 0, len(m.alerts))
+	for _, alert := range m.alerts {
+		alerts = append(alerts, *alert)
+	}
+
+	order := map[string]int{StateFiring: 0, StatePending: 1, StateResolved: 2}
+	sort.Slice(alerts, func(i, j int) bool {
+		if order[alerts[i].State] != order[alerts[j].State] {
+			return order[alerts[i].State] < order[alerts[j].State]
+		}
+		if alerts[i].Rule != alerts[j].Rule {
+			return alerts[i].Rule < alerts[j].Rule
+		}
+		return alerts[i].Goction < alerts[j].Goction
+	})
+	return alerts
+}
+
+// Evaluate checks every rule at the given time, updates alert states and sends notifications.
+// Alerts of goctions a rule no longer applies to, and alerts resolved for longer than ResolvedRetention, are dropped.
+func (m *Manager) Evaluate(now time.Time) {
+	set := m.set.Load()
+	evaluated := make(map[string]bool)
+	for _, r := range set.rules {
+		for _, goction := range m.goctionsFor(set, r) {
+			value, active, message := m.check(r, goction, now)
+			m.transition(set, r, goction, value, active, message, now)
+			evaluated[r.Name+"/"+goction] = true
+		}
+	}
+
+	m.mu.Lock()
+	for key, alert := range m.alerts {
+		if !evaluated[key] || alert.State == StateResolved && now.Sub(*alert.ResolvedAt) >= ResolvedRetention {
+			delete(m.alerts, key)
+		}
+	}
+	m.mu.Unlock()
+}
+
+// goctionsFor returns the goctions a rule applies to: its goction, or every known goction.
+// A no_success rule without goction skips the goctions that were never executed.
+func (m *Manager) goctionsFor(set *ruleSet, r rule) []string {
+	if r.Goction != "" {
+		return []string{r.Goction}
+	}
+
+	seen := make(map[string]bool)
+	for name := range m.stats.GetAllStats() {
+		seen[name] = true
+	}
+	if entries, err := os.ReadDir(set.config.GoctionsDir); err == nil && r.Type != RuleNoSuccess {
+		for _, entry := range entries {
+			if entry.IsDir() {
+				seen[entry.Name()] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// check computes the value of a rule for a goction and whether the alert condition holds
+func (m *Manager) check(r rule, goction string, now time.Time) (float64, bool, string) {
+	query := stats.HistoryQuery{Goction: goction, Since: now.Add(-r.window)}
+
+	var total, failures int
+	var durations []time.Duration
+	var lastSuccess time.Time
+	m.stats.EachRecord(query, func(record stats.ExecutionRecord) error {
+		total++
+		if record.Status == stats.StatusSuccess {
+			if record.Timestamp.After(lastSuccess) {
+				lastSuccess = record.Timestamp
+			}
+		} else {
+			failures++
+		}
+		durations = append(durations, record.Duration)
+		return nil
+	})
+
+	switch r.Type {
+	case RuleErrorRate:
+		if total < r.MinCalls {
+			return 0, false, ""
+		}
+		rate := float64(failures) / float64(total) * 100
+		return rate, rate > r.Threshold,
+			fmt.Sprintf("error rate of %s is %.1f%% (%d/%d) over %s, above %.1f%%", goction, rate, failures, total, r.window, r.Threshold)
+	case RuleLatency:
+		if total < r.MinCalls {
+			return 0, false, ""
+		}
+		latency := stats.Percentile(durations, r.Percentile).Seconds()
+		return latency, latency > r.Threshold,
+			fmt.Sprintf("p%g latency of %s is %.3fs over %s, above %.3fs", r.Percentile, goction, latency, r.window, r.Threshold)
+	default: // RuleNoSuccess
+		if !lastSuccess.IsZero() {
+			return now.Sub(lastSuccess).Seconds(), false, ""
+		}
+		return r.window.Seconds(), true,
+			fmt.Sprintf("%s has not been executed successfully in the last %s", goction, r.window)
+	}
+}
+
+func (m *Manager) transition(set *ruleSet, r rule, goction string, value float64, active bool, message string, now time.Time) {
+	key := r.Name + "/" + goction
+
+	m.mu.Lock()
+	alert, exists := m.alerts[key]
+	if !exists {
+		if !active {
+			m.mu.Unlock()
+			return
+		}
+		alert = &Alert{Rule: r.Name, Type: r.Type, Goction: goction, Threshold: r.Threshold}
+		m.alerts[key] = alert
+	}
+
+	alert.Value = value
+	var notify bool
+	switch {
+	case active && (alert.State == "" || alert.State == StateResolved):
+		at := now
+		alert.State = StatePending
+		alert.ActiveAt = &at
+		alert.FiredAt = nil
+		alert.ResolvedAt = nil
+		alert.Message = message
+		if r.pendingFor == 0 {
+			alert.State = StateFiring
+			alert.FiredAt = &at
+			notify = true
+		}
+	case active && alert.State == StatePending:
+		alert.Message = message
+		if now.Sub(*alert.ActiveAt) >= r.pendingFor {
+			at := now
+			alert.State = StateFiring
+			alert.FiredAt = &at
+			notify = true
+		}
+	case active:
+		alert.Message = message
+	case alert.State == StatePending:
+		// The condition cleared before the alert fired: forget it without notifying
+		delete(m.alerts, key)
+	case alert.State == StateFiring:
+		at := now
+		alert.State = StateResolved
+		alert.ResolvedAt = &at
+		alert.Message = fmt.Sprintf("alert %s resolved for %s", r.Name, goction)
+		notify = true
+	}
+	snapshot := *alert
+	m.mu.Unlock()
+
+	if !notify {
+		return
+	}
+
+	entry := m.logger.WithFields(logrus.Fields{
+		"alert":              r.Name,
+		logging.FieldGoction: goction,
+		"state":              snapshot.State,
+	})
+	if snapshot.State == StateFiring {
+		entry.Warn(snapshot.Message)
+	} else {
+		entry.Info(snapshot.Message)
+	}
+
+	for _, name := range r.Channels {
+		go m.notify(set.config.Alerts.SMTP, set.channels[name], snapshot)
+	}
+}
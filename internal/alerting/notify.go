@@ -0,0 +1,149 @@
+package alerting
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"goction/internal/config"
+	"goction/internal/runner"
+	"goction/internal/stats"
+)
+
+// Channel types
+const (
+	ChannelWebhook = "webhook"
+	ChannelEmail   = "email"
+	ChannelGoction = "goction"
+)
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// Notification is the JSON payload sent to webhooks and passed to goction channels
+type Notification struct {
+	Status string    `json:"status"`
+	Alert  Alert     `json:"alert"`
+	SentAt time.Time `json:"sent_at"`
+}
+
+func validateChannel(channel config.AlertChannel, cfg *config.Config) error {
+	if channel.Name == "" {
+		return fmt.Errorf("alert channel without a name")
+	}
+	switch channel.Type {
+	case ChannelWebhook:
+		if channel.URL == "" {
+			return fmt.Errorf("alert channel %q: webhook requires a url", channel.Name)
+		}
+	case ChannelEmail:
+		if len(channel.To) == 0 {
+			return fmt.Errorf("alert channel %q: email requires at least one recipient in to", channel.Name)
+		}
+		if cfg.Alerts.SMTP.Host == "" || cfg.Alerts.SMTP.From == "" {
+			return fmt.Errorf("alert channel %q: email requires alerts.smtp.host and alerts.smtp.from", channel.Name)
+		}
+	case ChannelGoction:
+		if channel.Goction == "" {
+			return fmt.Errorf("alert channel %q: goction channel requires a goction", channel.Name)
+		}
+	default:
+		return fmt.Errorf("alert channel %q: unknown type %q (expected %s, %s or %s)", channel.Name, channel.Type, ChannelWebhook, ChannelEmail, ChannelGoction)
+	}
+	return nil
+}
+
+func (m *Manager) notify(smtp config.SMTPConfig, channel config.AlertChannel, alert Alert) {
+	notification := Notification{Status: alert.State, Alert: alert, SentAt: time.Now()}
+
+	var err error
+	switch channel.Type {
+	case ChannelWebhook:
+		err = sendWebhook(channel.URL, notification)
+	case ChannelEmail:
+		err = sendEmail(smtp, channel.To, notification)
+	case ChannelGoction:
+		err = m.runGoction(channel.Goction, notification)
+	}
+
+	entry := m.logger.WithField("alert", alert.Rule).WithField("channel", channel.Name)
+	if err != nil {
+		entry.WithError(err).Error("Failed to send alert notification")
+		return
+	}
+	entry.Info("Alert notification sent")
+}
+
+func sendWebhook(url string, notification Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	resp, err := httpClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+func sendEmail(cfg config.SMTPConfig, to []string, notification Notification) error {
+	port := cfg.Port
+	if port == 0 {
+		port = 25
+	}
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(port))
+
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+
+	alert := notification.Alert
+	subject := fmt.Sprintf("[goction] %s: %s (%s)", strings.ToUpper(alert.State), alert.Rule, alert.Goction)
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "From: %s\r\n", cfg.From)
+	fmt.Fprintf(&body, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&body, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&body, "Date: %s\r\n", notification.SentAt.Format(time.RFC1123Z))
+	body.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	fmt.Fprintf(&body, "%s\r\n\r\n", alert.Message)
+	fmt.Fprintf(&body, "Rule: %s (%s)\r\nGoction: %s\r\nState: %s\r\nValue: %g\r\nThreshold: %g\r\n",
+		alert.Rule, alert.Type, alert.Goction, alert.State, alert.Value, alert.Threshold)
+
+	if err := smtp.SendMail(addr, auth, cfg.From, to, []byte(body.String())); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// runGoction executes a goction with the JSON-encoded notification as its only argument
+func (m *Manager) runGoction(name string, notification Notification) error {
+	if m.runner == nil {
+		return fmt.Errorf("no runner available to execute goction %q", name)
+	}
+
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	_, err = m.runner.Run(runner.Request{
+		Goction: name,
+		Args:    []string{string(payload)},
+		Caller:  "alert:" + notification.Alert.Rule,
+		Trigger: stats.TriggerAlert,
+	})
+	return err
+}
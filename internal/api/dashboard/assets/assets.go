@@ -0,0 +1,92 @@
+// Package assets embeds the stylesheet and images of the dashboard, so that it works without network access.
+//
+// Every file is served under a name containing a hash of its content, e.g. /static/dashboard.1a2b3c4d.css,
+// which browsers may cache forever: a new build changing a file also changes its URL.
+package assets
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+)
+
+// Prefix is the URL path under which the assets are served
+const Prefix = "/static/"
+
+//go:embed static
+var files embed.FS
+
+type asset struct {
+	name string
+	hash string
+	data []byte
+}
+
+var (
+	// byName maps the file names to their assets
+	byName = map[string]*asset{}
+	// byHashedName maps the content-hashed names to their assets
+	byHashedName = map[string]*asset{}
+)
+
+func init() {
+	fs.WalkDir(files, "static", func(p string, entry fs.DirEntry, err error) error {
+		if err != nil || entry.IsDir() {
+			return err
+		}
+		data, err := files.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		a := &asset{name: strings.TrimPrefix(p, "static/"), hash: hex.EncodeToString(sum[:])[:12], data: data}
+		byName[a.name] = a
+		byHashedName[hashedName(a.name, a.hash)] = a
+		return nil
+	})
+}
+
+// hashedName inserts hash before the extension of name
+func hashedName(name, hash string) string {
+	ext := path.Ext(name)
+	return strings.TrimSuffix(name, ext) + "." + hash + ext
+}
+
+// Path returns the content-hashed URL of an asset. It panics for unknown assets, which are programming errors.
+func Path(name string) string {
+	a, ok := byName[name]
+	if !ok {
+		panic("assets: unknown asset " + name)
+	}
+	return Prefix + hashedName(a.name, a.hash)
+}
+
+// Handler serves the assets under Prefix. Content-hashed names are cached for a year;
+// plain names are revalidated with their ETag on every use.
+func Handler() http.Handler {
+	return http.StripPrefix(Prefix, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if a, ok := byHashedName[r.URL.Path]; ok {
+			w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+			serve(w, r, a)
+			return
+		}
+		if a, ok := byName[r.URL.Path]; ok {
+			w.Header().Set("Cache-Control", "no-cache")
+			serve(w, r, a)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+}
+
+func serve(w http.ResponseWriter, r *http.Request, a *asset) {
+	w.Header().Set("ETag", `"`+a.hash+`"`)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(w, r, a.name, time.Time{}, bytes.NewReader(a.data))
+}
@@ -0,0 +1,213 @@
+// Goction dashboard charts: small SVG bar, line and heatmap charts with hover tooltips.
+// Bundled with the dashboard so that it needs no external script.
+(function() {
+    "use strict";
+
+    var SVG = "http://www.w3.org/2000/svg";
+    var height = 220;
+    var margin = {top: 10, right: 12, bottom: 28, left: 52};
+    var tooltip = null;
+
+    function element(name, attrs, parent) {
+        var el = document.createElementNS(SVG, name);
+        Object.keys(attrs || {}).forEach(function(key) {
+            el.setAttribute(key, attrs[key]);
+        });
+        if (parent) {
+            parent.appendChild(el);
+        }
+        return el;
+    }
+
+    function showTooltip(event, html) {
+        if (!tooltip) {
+            tooltip = document.createElement("div");
+            tooltip.className = "chart-tooltip";
+            document.body.appendChild(tooltip);
+        }
+        tooltip.innerHTML = html;
+        tooltip.style.left = (event.pageX + 12) + "px";
+        tooltip.style.top = (event.pageY + 12) + "px";
+        tooltip.hidden = false;
+    }
+
+    function hideTooltip() {
+        if (tooltip) {
+            tooltip.hidden = true;
+        }
+    }
+
+    function escapeHTML(text) {
+        return String(text).replace(/&/g, "&amp;").replace(/</g, "&lt;").replace(/>/g, "&gt;");
+    }
+
+    // niceMax rounds max up to 1, 2 or 5 times a power of ten
+    function niceMax(max) {
+        if (max <= 0) {
+            return 1;
+        }
+        var power = Math.pow(10, Math.floor(Math.log(max) / Math.LN10));
+        var steps = [1, 2, 5, 10];
+        for (var i = 0; i < steps.length; i++) {
+            if (steps[i] * power >= max) {
+                return steps[i] * power;
+            }
+        }
+        return 10 * power;
+    }
+
+    function legend(container, series) {
+        var list = document.createElement("div");
+        list.className = "chart-legend";
+        series.forEach(function(s) {
+            var item = document.createElement("span");
+            var swatch = document.createElement("span");
+            swatch.className = "chart-swatch";
+            swatch.style.backgroundColor = s.color;
+            item.appendChild(swatch);
+            item.appendChild(document.createTextNode(s.name));
+            list.appendChild(item);
+        });
+        container.appendChild(list);
+    }
+
+    // frame draws the axes of a chart and returns its SVG element and scales
+    function frame(container, labels, max, format) {
+        container.innerHTML = "";
+        var width = Math.max(container.clientWidth, 300);
+        var svg = element("svg", {width: width, height: height, viewBox: "0 0 " + width + " " + height, "class": "chart"}, container);
+        var plot = {
+            svg: svg,
+            width: width - margin.left - margin.right,
+            height: height - margin.top - margin.bottom,
+            max: niceMax(max)
+        };
+        plot.step = plot.width / Math.max(labels.length, 1);
+        plot.x = function(i) { return margin.left + i * plot.step; };
+        plot.y = function(value) { return margin.top + plot.height - value / plot.max * plot.height; };
+
+        for (var t = 0; t <= 4; t++) {
+            var value = plot.max * t / 4;
+            element("line", {x1: margin.left, x2: margin.left + plot.width, y1: plot.y(value), y2: plot.y(value), "class": "chart-grid"}, svg);
+            element("text", {x: margin.left - 6, y: plot.y(value) + 4, "text-anchor": "end"}, svg).textContent = format(value);
+        }
+        var every = Math.max(1, Math.ceil(labels.length / 6));
+        labels.forEach(function(label, i) {
+            if (i % every === 0) {
+                element("text", {x: plot.x(i) + plot.step / 2, y: height - 8, "text-anchor": "middle"}, svg).textContent = label.short;
+            }
+        });
+        return plot;
+    }
+
+    // hover adds an invisible column per label showing the values of every series in a tooltip
+    function hover(plot, labels, series, format) {
+        labels.forEach(function(label, i) {
+            var column = element("rect", {x: plot.x(i), y: margin.top, width: plot.step, height: plot.height, "class": "chart-hover"}, plot.svg);
+            column.addEventListener("mousemove", function(event) {
+                var html = "<strong>" + escapeHTML(label.long) + "</strong>";
+                series.forEach(function(s) {
+                    html += "<br><span class=\"chart-swatch\" style=\"background-color:" + s.color + "\"></span>" +
+                        escapeHTML(s.name) + ": " + escapeHTML(format(s.values[i]));
+                });
+                showTooltip(event, html);
+            });
+            column.addEventListener("mouseleave", hideTooltip);
+        });
+    }
+
+    // bars draws the series stacked in one bar per label
+    function bars(container, labels, series, format) {
+        var max = 0;
+        labels.forEach(function(_, i) {
+            var sum = 0;
+            series.forEach(function(s) { sum += s.values[i]; });
+            max = Math.max(max, sum);
+        });
+        var plot = frame(container, labels, max, format);
+        labels.forEach(function(_, i) {
+            var base = 0;
+            series.forEach(function(s) {
+                var value = s.values[i];
+                if (value > 0) {
+                    element("rect", {
+                        x: plot.x(i) + plot.step * 0.15,
+                        y: plot.y(base + value),
+                        width: plot.step * 0.7,
+                        height: plot.y(base) - plot.y(base + value),
+                        fill: s.color
+                    }, plot.svg);
+                }
+                base += value;
+            });
+        });
+        hover(plot, labels, series, format);
+        legend(container, series);
+    }
+
+    // lines draws one line per series; null values leave gaps
+    function lines(container, labels, series, format) {
+        var max = 0;
+        series.forEach(function(s) {
+            s.values.forEach(function(value) { max = Math.max(max, value || 0); });
+        });
+        var plot = frame(container, labels, max, format);
+        series.forEach(function(s) {
+            var path = "";
+            var pen = "M";
+            s.values.forEach(function(value, i) {
+                if (value === null) {
+                    pen = "M";
+                    return;
+                }
+                var x = plot.x(i) + plot.step / 2;
+                path += pen + x.toFixed(1) + " " + plot.y(value).toFixed(1) + " ";
+                pen = "L";
+                element("circle", {cx: x, cy: plot.y(value), r: 2.5, fill: s.color}, plot.svg);
+            });
+            if (path) {
+                element("path", {d: path, fill: "none", stroke: s.color, "stroke-width": 2}, plot.svg);
+            }
+        });
+        hover(plot, labels, series, function(value) { return value === null ? "no executions" : format(value); });
+        legend(container, series);
+    }
+
+    // heatmap draws counts[day][hour] as a grid whose cells are more opaque for higher counts
+    function heatmap(container, counts, color) {
+        container.innerHTML = "";
+        var days = ["Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"];
+        var width = Math.max(container.clientWidth, 300);
+        var left = 40;
+        var cell = Math.min((width - left) / 24, 28);
+        var svg = element("svg", {width: width, height: cell * 7 + 24, "class": "chart"}, container);
+        var max = 0;
+        counts.forEach(function(row) {
+            row.forEach(function(count) { max = Math.max(max, count); });
+        });
+
+        counts.forEach(function(row, day) {
+            element("text", {x: left - 6, y: day * cell + cell / 2 + 4, "text-anchor": "end"}, svg).textContent = days[day];
+            row.forEach(function(count, hour) {
+                var rect = element("rect", {
+                    x: left + hour * cell + 1,
+                    y: day * cell + 1,
+                    width: cell - 2,
+                    height: cell - 2,
+                    rx: 2,
+                    fill: count > 0 ? color : "#363636",
+                    "fill-opacity": count > 0 ? 0.2 + 0.8 * count / max : 1
+                }, svg);
+                rect.addEventListener("mousemove", function(event) {
+                    showTooltip(event, "<strong>" + days[day] + " " + hour + ":00-" + (hour + 1) + ":00</strong><br>" + count + " executions");
+                });
+                rect.addEventListener("mouseleave", hideTooltip);
+            });
+        });
+        for (var hour = 0; hour < 24; hour += 3) {
+            element("text", {x: left + hour * cell + cell / 2, y: cell * 7 + 16, "text-anchor": "middle"}, svg).textContent = hour + "h";
+        }
+    }
+
+    window.GoctionCharts = {bars: bars, lines: lines, heatmap: heatmap};
+})();
@@ -0,0 +1,211 @@
+// Goction source editor: a textarea over a syntax highlighted copy of its text, with line numbers
+// and markers on the lines of compiler errors. Bundled with the dashboard so that it needs no external script.
+(function() {
+    "use strict";
+
+    var goKeywords = words("break case chan const continue default defer else fallthrough for func go goto if import interface map package range return select struct switch type var");
+    var goTypes = words("any bool byte comparable complex64 complex128 error float32 float64 int int8 int16 int32 int64 rune string uint uint8 uint16 uint32 uint64 uintptr");
+    var goBuiltins = words("append cap clear close complex copy delete imag len make max min new panic print println real recover true false iota nil");
+    var modKeywords = words("module go toolchain require replace exclude retract");
+
+    // tokenPattern matches, in order: comments, strings, numbers, identifiers and any other character
+    var tokenPattern = /(\/\/[^\n]*|\/\*[\s\S]*?(?:\*\/|$))|(`[^`]*`?|"(?:\\.|[^"\\\n])*"?|'(?:\\.|[^'\\\n])*'?)|(\b(?:0[xX][0-9a-fA-F_]+|\d[\d_]*(?:\.\d*)?(?:[eE][+-]?\d+)?)\b)|([A-Za-z_]\w*)|([\s\S])/g;
+
+    function words(list) {
+        var set = {};
+        list.split(" ").forEach(function(word) { set[word] = true; });
+        return set;
+    }
+
+    function escapeHTML(text) {
+        return text.replace(/&/g, "&amp;").replace(/</g, "&lt;").replace(/>/g, "&gt;");
+    }
+
+    function span(kind, text) {
+        return "<span class=\"tok-" + kind + "\">" + escapeHTML(text) + "</span>";
+    }
+
+    // languageOf returns the language of a file from its name: go, gomod or json
+    function languageOf(name) {
+        if (/\.json$/.test(name)) {
+            return "json";
+        }
+        if (/^go\.(mod|sum)$/.test(name)) {
+            return "gomod";
+        }
+        return "go";
+    }
+
+    // highlight returns the HTML of text with its tokens wrapped in spans
+    function highlight(text, language) {
+        var html = "";
+        var match;
+        tokenPattern.lastIndex = 0;
+        while ((match = tokenPattern.exec(text)) !== null) {
+            if (match[1] !== undefined) {
+                html += language === "json" ? escapeHTML(match[1]) : span("comment", match[1]);
+            } else if (match[2] !== undefined) {
+                var key = language === "json" && /^\s*:/.test(text.slice(tokenPattern.lastIndex));
+                html += span(key ? "key" : "string", match[2]);
+            } else if (match[3] !== undefined) {
+                html += span("number", match[3]);
+            } else if (match[4] !== undefined) {
+                html += identifier(match[4], language);
+            } else {
+                html += escapeHTML(match[5]);
+            }
+        }
+        return html;
+    }
+
+    function identifier(word, language) {
+        switch (language) {
+        case "go":
+            if (goKeywords[word]) {
+                return span("keyword", word);
+            }
+            if (goTypes[word]) {
+                return span("type", word);
+            }
+            if (goBuiltins[word]) {
+                return span("builtin", word);
+            }
+            break;
+        case "gomod":
+            if (modKeywords[word]) {
+                return span("keyword", word);
+            }
+            break;
+        case "json":
+            if (word === "true" || word === "false" || word === "null") {
+                return span("builtin", word);
+            }
+            break;
+        }
+        return escapeHTML(word);
+    }
+
+    // create turns a textarea into an editor for a file named name
+    function create(textarea, name) {
+        var language = languageOf(name);
+        var root = document.createElement("div");
+        root.className = "editor";
+        var gutter = document.createElement("div");
+        gutter.className = "editor-gutter";
+        var body = document.createElement("div");
+        body.className = "editor-body";
+        var code = document.createElement("pre");
+        code.className = "editor-highlight";
+        code.setAttribute("aria-hidden", "true");
+
+        textarea.parentNode.insertBefore(root, textarea);
+        root.appendChild(gutter);
+        root.appendChild(body);
+        body.appendChild(code);
+        body.appendChild(textarea);
+        textarea.classList.add("editor-input");
+        textarea.spellcheck = false;
+        textarea.wrap = "off";
+
+        var markers = [];
+        var lineCount = 0;
+
+        function render() {
+            // A trailing newline needs a character after it for the last line to take room
+            code.innerHTML = highlight(textarea.value, language) + "\n ";
+            var count = textarea.value.split("\n").length;
+            if (count !== lineCount) {
+                lineCount = count;
+                renderGutter();
+            }
+            renderMarkers();
+            sync();
+        }
+
+        function renderGutter() {
+            var errors = {};
+            markers.forEach(function(marker) {
+                errors[marker.line] = (errors[marker.line] ? errors[marker.line] + "\n" : "") + marker.message;
+            });
+            var html = "";
+            for (var line = 1; line <= lineCount; line++) {
+                if (errors[line]) {
+                    html += "<div class=\"has-error\" title=\"" + escapeHTML(errors[line]).replace(/"/g, "&quot;") + "\">" + line + "</div>";
+                } else {
+                    html += "<div>" + line + "</div>";
+                }
+            }
+            gutter.innerHTML = html + "<div>&nbsp;</div>";
+        }
+
+        function renderMarkers() {
+            var lineHeight = parseFloat(window.getComputedStyle(code).lineHeight) || 20;
+            var top = parseFloat(window.getComputedStyle(code).paddingTop) || 0;
+            markers.forEach(function(marker) {
+                if (marker.line > lineCount) {
+                    return;
+                }
+                var line = document.createElement("div");
+                line.className = "editor-error-line";
+                line.style.top = (top + (marker.line - 1) * lineHeight) + "px";
+                line.style.height = lineHeight + "px";
+                code.appendChild(line);
+            });
+        }
+
+        function sync() {
+            code.scrollTop = textarea.scrollTop;
+            code.scrollLeft = textarea.scrollLeft;
+            gutter.scrollTop = textarea.scrollTop;
+        }
+
+        textarea.addEventListener("input", render);
+        textarea.addEventListener("scroll", sync);
+        textarea.addEventListener("keydown", function(event) {
+            if (event.key !== "Tab" || event.ctrlKey || event.metaKey || event.altKey || event.shiftKey) {
+                return;
+            }
+            // Insert a tab, as gofmt indents with tabs, instead of leaving the editor
+            event.preventDefault();
+            var start = textarea.selectionStart;
+            textarea.setRangeText("\t", start, textarea.selectionEnd, "end");
+            render();
+        });
+
+        var editor = {
+            name: name,
+            element: root,
+            getValue: function() {
+                return textarea.value;
+            },
+            setValue: function(value) {
+                textarea.value = value;
+                render();
+            },
+            // setMarkers marks the lines of errors, a list of {line, column, message}
+            setMarkers: function(errors) {
+                markers = errors || [];
+                renderGutter();
+                render();
+            },
+            // goTo places the cursor at the start of a line and column and scrolls it into view
+            goTo: function(line, column) {
+                var lines = textarea.value.split("\n");
+                var offset = 0;
+                for (var i = 0; i < line - 1 && i < lines.length; i++) {
+                    offset += lines[i].length + 1;
+                }
+                offset += Math.max((column || 1) - 1, 0);
+                textarea.focus();
+                textarea.setSelectionRange(offset, offset);
+                var lineHeight = parseFloat(window.getComputedStyle(code).lineHeight) || 20;
+                textarea.scrollTop = Math.max((line - 3) * lineHeight, 0);
+                sync();
+            }
+        };
+        render();
+        return editor;
+    }
+
+    window.GoctionEditor = {create: create, highlight: highlight, languageOf: languageOf};
+})();
@@ -2,43 +2,47 @@ package dashboard
 
 import (
 	"bufio"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"goction/internal/api/dashboard/templates"
 	"goction/internal/config"
+	"goction/internal/execlog"
+	"goction/internal/goctions"
+	"goction/internal/logging"
+	"goction/internal/manifest"
+	"goction/internal/runner"
 	"goction/internal/stats"
+	"goction/internal/sysmetrics"
 	"goction/internal/viewmodels"
 
+	"github.com/gorilla/mux"
 	"github.com/gorilla/sessions"
-	"github.com/shirou/gopsutil/v3/cpu"
-	"github.com/shirou/gopsutil/v3/host"
-	"github.com/shirou/gopsutil/v3/mem"
 )
 
-func getSystemStats() viewmodels.SystemStats {
-	cpuPercent, _ := cpu.Percent(time.Second, false)
-	memInfo, _ := mem.VirtualMemory()
-	uptime, _ := host.Uptime()
-
-	return viewmodels.SystemStats{
-		CPUUsage:    cpuPercent[0],
-		MemoryUsage: memInfo.UsedPercent,
-		Uptime:      time.Duration(uptime) * time.Second,
-	}
-}
-
-func LoginHandler(cfg *config.Config, store *sessions.CookieStore) http.HandlerFunc {
+func LoginHandler(getConfig func() *config.Config, store *sessions.CookieStore) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		cfg := getConfig()
 		session, _ := store.Get(r, "goction-dashboard")
 
 		if r.Method == "POST" {
 			username := r.FormValue("username")
 			password := r.FormValue("password")
 
-			if username == cfg.DashboardUsername && password == cfg.DashboardPassword {
+			// Both are compared in full so that the response time does not reveal which one is wrong
+			validUsername := subtle.ConstantTimeCompare([]byte(username), []byte(cfg.DashboardUsername)) == 1
+			validPassword := subtle.ConstantTimeCompare([]byte(password), []byte(cfg.DashboardPassword)) == 1
+			if validUsername && validPassword && cfg.DashboardPassword != "" {
 				session.Values["authenticated"] = true
+				session.Values["username"] = username
 				session.Save(r, w)
 				http.Redirect(w, r, "/", http.StatusSeeOther)
 				return
@@ -53,57 +57,374 @@ func LogoutHandler(store *sessions.CookieStore) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		session, _ := store.Get(r, "goction-dashboard")
 		session.Values["authenticated"] = false
+		delete(session.Values, "username")
 		session.Save(r, w)
 		http.Redirect(w, r, "/login", http.StatusSeeOther)
 	}
 }
 
-func getRecentLogs(logFilePath string, numLines int) ([]string, error) {
-    file, err := os.Open(logFilePath)
-    if err != nil {
-        return nil, err
-    }
-    defer file.Close()
+// DashboardHandler renders the dashboard. The system metrics come from the latest background sample,
+// and the page then keeps itself up to date through /events.
+func DashboardHandler(getConfig func() *config.Config, statsManager *stats.Manager, system func() (sysmetrics.Sample, bool)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cfg := getConfig()
+		allStats := statsManager.GetAllStats()
 
-	var lines []string
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		lines = append(lines, scanner.Text())
-		if len(lines) > numLines {
-			lines = lines[1:]
+		recent, _ := statsManager.QueryHistory(stats.HistoryQuery{Limit: 10})
+
+		data := viewmodels.DashboardData{
+			Config:           cfg,
+			Stats:            allStats,
+			RecentExecutions: recent.Records,
+			GoctionVersion:   config.GoctionVersion,
+		}
+
+		// The rest of the dashboard stays available when the goctions directory cannot be read
+		goctions, err := listGoctions(cfg.GoctionsDir)
+		if err != nil {
+			data.GoctionsError = err.Error()
+		} else {
+			data.Goctions = goctions
+			// Statistics of goctions removed by hand are kept until the next start of the server, see Server.Start
+			for name := range allStats {
+				if !installed(goctions, name) {
+					delete(allStats, name)
+				}
+			}
+		}
+		data.System, data.HasSystem = system()
+
+		templates.WriteDashboard(w, data)
+	}
+}
+
+// listGoctions returns the goctions installed in dir with their manifest
+func listGoctions(dir string) ([]viewmodels.Goction, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read goctions directory: %w", err)
+	}
+
+	var goctions []viewmodels.Goction
+	for _, file := range files {
+		if !file.IsDir() {
+			continue
+		}
+		goction := viewmodels.Goction{Name: file.Name()}
+		if m, err := manifest.Load(filepath.Join(dir, file.Name())); err != nil {
+			goction.Error = err.Error()
+		} else {
+			goction.Description, goction.Args, goction.Disabled = m.Description, m.Args, m.Disabled
 		}
+		goctions = append(goctions, goction)
 	}
+	return goctions, nil
+}
 
-	if err := scanner.Err(); err != nil {
-		return nil, err
+func installed(goctions []viewmodels.Goction, name string) bool {
+	for _, goction := range goctions {
+		if goction.Name == name {
+			return true
+		}
 	}
+	return false
+}
 
-	return lines, nil
+// RunResult is the response of RunHandler
+type RunResult struct {
+	ExecutionID string `json:"execution_id,omitempty"`
+	Status      string `json:"status,omitempty"`
+	Result      string `json:"result,omitempty"`
+	Error       string `json:"error,omitempty"`
+	Duration    string `json:"duration,omitempty"`
 }
 
-func DashboardHandler(cfg *config.Config, statsManager *stats.Manager) http.HandlerFunc {
+// RunHandler executes a goction from its Run form, with the dashboard user as caller.
+// The request body is a JSON object with the args of the goction.
+func RunHandler(getConfig func() *config.Config, store *sessions.CookieStore, run func(runner.Request) (stats.ExecutionRecord, error)) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		allStats := statsManager.GetAllStats()
-		history := statsManager.GetAllHistory() // Utilisation de la nouvelle méthode
+		if !isJSONRequest(w, r) {
+			return
+		}
+		var body struct {
+			Args []string `json:"args"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeRunError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+			return
+		}
 
-		recentLogs, err := getRecentLogs(cfg.LogFile, 50) // Get last 50 lines
+		name := mux.Vars(r)["goction"]
+		m, err := manifest.Load(filepath.Join(getConfig().GoctionsDir, name))
 		if err != nil {
-			// Handle error, maybe log it
-			recentLogs = []string{"Error reading logs: " + err.Error()}
+			writeRunError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		for i, arg := range m.Args {
+			if arg.Required && (i >= len(body.Args) || body.Args[i] == "") {
+				writeRunError(w, http.StatusBadRequest, fmt.Sprintf("argument %s is required", arg.Name))
+				return
+			}
 		}
 
-		data := viewmodels.DashboardData{
-			Config:         cfg,
-			Stats:          allStats,
-			History:        history,
-			RecentLogs:     recentLogs,
+		execute(w, r, store, run, name, body.Args)
+	}
+}
+
+// RerunHandler executes a goction again with the arguments of a recorded execution
+func RerunHandler(getConfig func() *config.Config, statsManager *stats.Manager, store *sessions.CookieStore, run func(runner.Request) (stats.ExecutionRecord, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !isJSONRequest(w, r) {
+			return
+		}
+		record, ok := statsManager.GetExecution(mux.Vars(r)["id"])
+		if !ok {
+			writeRunError(w, http.StatusNotFound, "execution not found")
+			return
+		}
+		if reason := rerunBlocked(getConfig(), record); reason != "" {
+			writeRunError(w, http.StatusConflict, reason)
+			return
+		}
+
+		execute(w, r, store, run, record.Goction, record.Args)
+	}
+}
+
+// rerunBlocked returns why an execution cannot be run again with its recorded arguments, or an empty string
+func rerunBlocked(cfg *config.Config, record stats.ExecutionRecord) string {
+	m, err := manifest.Load(filepath.Join(cfg.GoctionsDir, record.Goction))
+	if err != nil {
+		return err.Error()
+	}
+	for i, arg := range m.Args {
+		if arg.Secret && i < len(record.Args) {
+			return fmt.Sprintf("argument %s is secret and was not recorded; use the Run form of the dashboard instead", arg.Name)
+		}
+	}
+	return ""
+}
+
+// execute runs a goction with the dashboard user as caller and writes the outcome as a RunResult
+func execute(w http.ResponseWriter, r *http.Request, store *sessions.CookieStore, run func(runner.Request) (stats.ExecutionRecord, error), name string, args []string) {
+	record, err := run(runner.Request{
+		Goction: name,
+		Args:    args,
+		Caller:  sessionUser(store, r),
+		Trigger: stats.TriggerDashboard,
+	})
+
+	var loadErr *runner.LoadError
+	if errors.As(err, &loadErr) {
+		writeRunError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if errors.Is(err, runner.ErrDisabled) {
+		writeRunError(w, http.StatusConflict, err.Error())
+		return
+	}
+	result := RunResult{
+		ExecutionID: record.ID,
+		Status:      record.Status,
+		Result:      record.Result,
+		Error:       record.Error,
+		Duration:    record.Duration.String(),
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// sessionUser returns the name of the user logged in to the dashboard
+func sessionUser(store *sessions.CookieStore, r *http.Request) string {
+	session, _ := store.Get(r, "goction-dashboard")
+	username, _ := session.Values["username"].(string)
+	return username
+}
+
+// isJSONRequest rejects requests that are not JSON.
+// Browsers cannot send a cross-site JSON request without a CORS preflight, which the server never allows.
+func isJSONRequest(w http.ResponseWriter, r *http.Request) bool {
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		return true
+	}
+	writeRunError(w, http.StatusUnsupportedMediaType, "expected a JSON request")
+	return false
+}
+
+func writeRunError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(RunResult{Error: message})
+}
+
+// historyPageSize is the number of executions per history page
+const historyPageSize = 25
+
+// HistoryHandler shows the executions of a goction, newest first, optionally filtered by status
+func HistoryHandler(statsManager *stats.Manager) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		data := viewmodels.HistoryData{
+			Goction:        mux.Vars(r)["goction"],
+			Status:         r.URL.Query().Get("status"),
+			Cursor:         r.URL.Query().Get("cursor"),
 			GoctionVersion: config.GoctionVersion,
 		}
 
-		templates.WriteDashboard(w, data)
+		page, err := statsManager.QueryHistory(stats.HistoryQuery{
+			Goction:  data.Goction,
+			Statuses: stats.ParseStatuses(data.Status),
+			Limit:    historyPageSize,
+			Cursor:   data.Cursor,
+		})
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			data.Error = err.Error()
+		}
+		data.Records, data.Total, data.NextCursor = page.Records, page.Total, page.NextCursor
+
+		templates.WriteHistory(w, data)
 	}
 }
 
+// ExecutionHandler shows an execution: its arguments, result, error, timing and output
+func ExecutionHandler(getConfig func() *config.Config, statsManager *stats.Manager, getLogs func() *execlog.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := mux.Vars(r)["id"]
+		data := viewmodels.ExecutionData{
+			ExecutionID:    id,
+			GoctionVersion: config.GoctionVersion,
+		}
+		data.Record, data.Found = statsManager.GetExecution(id)
+		if data.Found {
+			data.RerunBlocked = rerunBlocked(getConfig(), data.Record)
+		}
+
+		lines, err := getLogs().Read(id)
+		switch {
+		case errors.Is(err, execlog.ErrNotFound), errors.Is(err, execlog.ErrInvalidID):
+			data.LogsError = err.Error()
+		case err != nil:
+			data.LogsError = "Error reading logs: " + err.Error()
+		}
+		data.Lines = lines
+
+		if !data.Found && data.LogsError != "" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		templates.WriteExecution(w, data)
+	}
+}
+
+// ExecutionLogsRedirectHandler sends the former execution logs page to the logs of the execution page
+func ExecutionLogsRedirectHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/executions/"+url.PathEscape(mux.Vars(r)["id"])+"#logs", http.StatusMovedPermanently)
+	}
+}
+
+// EditorHandler shows the source files of a goction in the editor, with its saved revisions
+func EditorHandler(getConfig func() *config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cfg := getConfig()
+		data := viewmodels.EditorData{
+			Goction:        mux.Vars(r)["goction"],
+			PluginMode:     cfg.PluginMode(),
+			GoctionVersion: config.GoctionVersion,
+		}
+		dir, err := goctions.Dir(cfg.GoctionsDir, data.Goction)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		if m, err := manifest.Load(dir); err != nil {
+			data.Error = err.Error()
+		} else {
+			data.Description, data.Args = m.Description, m.Args
+		}
+		if data.Files, err = goctions.ReadSources(cfg.GoctionsDir, data.Goction); err != nil {
+			data.Error = err.Error()
+		}
+		if data.Revisions, err = goctions.ListRevisions(cfg.GoctionsDir, data.Goction); err != nil {
+			data.Error = err.Error()
+		}
+
+		templates.WriteEditor(w, data)
+	}
+}
+
+// SaveResult is the response of SaveSourceHandler and RestoreRevisionHandler
+type SaveResult struct {
+	// Revision is the revision recorded by the save, with the source files of the goction
+	Revision *goctions.Revision    `json:"revision,omitempty"`
+	Build    *goctions.BuildResult `json:"build,omitempty"`
+	Error    string                `json:"error,omitempty"`
+}
+
+// SaveSourceHandler saves the source files edited in the editor and builds the goction.
+// The request body is a JSON object with the files to write; the other source files are left unchanged.
+func SaveSourceHandler(getConfig func() *config.Config, store *sessions.CookieStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !isJSONRequest(w, r) {
+			return
+		}
+		var body struct {
+			Files []goctions.File `json:"files"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeRunError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+			return
+		}
+		for _, file := range body.Files {
+			if !goctions.IsSourceFile(file.Name) {
+				writeRunError(w, http.StatusBadRequest, fmt.Sprintf("%q is not a source file of a goction", file.Name))
+				return
+			}
+		}
+
+		rev, build, err := goctions.Save(r.Context(), getConfig().GoctionsDir, mux.Vars(r)["goction"], sessionUser(store, r), body.Files)
+		writeSaveResult(w, rev, build, err)
+	}
+}
+
+// RestoreRevisionHandler brings the source files of a goction back to a saved revision and builds it
+func RestoreRevisionHandler(getConfig func() *config.Config, store *sessions.CookieStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !isJSONRequest(w, r) {
+			return
+		}
+		vars := mux.Vars(r)
+		rev, build, err := goctions.Restore(r.Context(), getConfig().GoctionsDir, vars["goction"], vars["id"], sessionUser(store, r))
+		writeSaveResult(w, rev, build, err)
+	}
+}
+
+// writeSaveResult writes the outcome of a save or a restore as a SaveResult.
+// A goction that does not compile is a successful save whose build failed.
+func writeSaveResult(w http.ResponseWriter, rev goctions.Revision, build *goctions.BuildResult, err error) {
+	result := SaveResult{Build: build}
+	if rev.ID != "" {
+		result.Revision = &rev
+	}
+	status := http.StatusOK
+	switch {
+	case errors.Is(err, goctions.ErrNotFound), errors.Is(err, goctions.ErrRevisionNotFound):
+		status = http.StatusNotFound
+	case err != nil:
+		status = http.StatusInternalServerError
+	}
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(result)
+}
+
 func AuthMiddleware(store *sessions.CookieStore, next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		session, _ := store.Get(r, "goction-dashboard")
@@ -115,8 +436,102 @@ func AuthMiddleware(store *sessions.CookieStore, next http.HandlerFunc) http.Han
 	}
 }
 
-func SetupRoutes(router *http.ServeMux, cfg *config.Config, statsManager *stats.Manager, store *sessions.CookieStore) {
-	router.HandleFunc("/login", LoginHandler(cfg, store))
+func SetupRoutes(router *http.ServeMux, getConfig func() *config.Config, statsManager *stats.Manager, store *sessions.CookieStore, system func() (sysmetrics.Sample, bool)) {
+	router.HandleFunc("/login", LoginHandler(getConfig, store))
 	router.HandleFunc("/logout", LogoutHandler(store))
-	router.HandleFunc("/", AuthMiddleware(store, DashboardHandler(cfg, statsManager)))
+	router.HandleFunc("/", AuthMiddleware(store, DashboardHandler(getConfig, statsManager, system)))
+}
+
+// logBacklog is the number of past entries sent when the live log panel connects
+const logBacklog = 50
+
+// logKeepAlive is how often an SSE comment is sent so that proxies keep an idle log stream open
+const logKeepAlive = 15 * time.Second
+
+// LogStreamHandler streams the goction log to the live log panel as server-sent events, one JSON entry per event.
+// The goction and level query parameters filter the entries; the last matching ones are sent first.
+func LogStreamHandler(getConfig func() *config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, err := logFilter(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+			return
+		}
+
+		path := getConfig().LogFile
+		backlog, _ := logging.Recent(path, filter, logBacklog)
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("X-Accel-Buffering", "no")
+		send := func(entry logging.Entry) {
+			data, _ := json.Marshal(entry)
+			fmt.Fprintf(w, "data: %s\n\n", data)
+		}
+		for _, entry := range backlog {
+			send(entry)
+		}
+		flusher.Flush()
+
+		entries := make(chan logging.Entry, 64)
+		go func() {
+			logging.FollowEntries(r.Context(), path, filter, func(entry logging.Entry) {
+				select {
+				case entries <- entry:
+				case <-r.Context().Done():
+				}
+			})
+		}()
+
+		keepAlive := time.NewTicker(logKeepAlive)
+		defer keepAlive.Stop()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case entry := <-entries:
+				send(entry)
+			case <-keepAlive.C:
+				fmt.Fprint(w, ": keep-alive\n\n")
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// LogDownloadHandler sends the entries of the current log file matching the goction and level query parameters
+func LogDownloadHandler(getConfig func() *config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter, err := logFilter(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		file, err := os.Open(getConfig().LogFile)
+		if err != nil {
+			http.Error(w, "Error reading logs: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer file.Close()
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="goction-%s.log"`, time.Now().Format("20060102-150405")))
+		scanner := bufio.NewScanner(file)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			if line := scanner.Text(); filter.Match(logging.ParseLine(line)) {
+				fmt.Fprintln(w, line)
+			}
+		}
+	}
+}
+
+func logFilter(r *http.Request) (logging.Filter, error) {
+	query := r.URL.Query()
+	return logging.NewFilter(query.Get("goction"), query.Get("level"), "", "")
 }
@@ -6,8 +6,8 @@ package templates
 
 //line internal/api/dashboard/templates/dashboard.qtpl:1
 import (
+	"goction/internal/api/dashboard/assets"
 	"goction/internal/viewmodels"
-	"strings"
 	"time"
 )
 
@@ -34,8 +34,16 @@ func StreamDashboard(qw422016 *qt422016.Writer, data viewmodels.DashboardData) {
     <meta charset="UTF-8">
     <meta name="viewport" content="width=device-width, initial-scale=1.0">
     <title>Goction Dashboard</title>
-    <link rel="stylesheet" href="https://cdn.jsdelivr.net/npm/bulma@0.9.3/css/bulma.min.css">
-    <script defer src="https://use.fontawesome.com/releases/v5.15.4/js/all.js"></script>
+    <link rel="stylesheet" href="`)
+//line internal/api/dashboard/templates/dashboard.qtpl:14
+	qw422016.E().S(assets.Path("dashboard.css"))
+//line internal/api/dashboard/templates/dashboard.qtpl:14
+	qw422016.N().S(`">
+    <link rel="icon" type="image/png" href="`)
+//line internal/api/dashboard/templates/dashboard.qtpl:15
+	qw422016.E().S(assets.Path("goction.png"))
+//line internal/api/dashboard/templates/dashboard.qtpl:15
+	qw422016.N().S(`">
     <style>
         body {
             display: flex;
@@ -49,20 +57,37 @@ func StreamDashboard(qw422016 *qt422016.Writer, data viewmodels.DashboardData) {
             max-height: 400px;
             overflow-y: auto;
         }
+        #log-lines .level-error, #log-lines .level-fatal, #log-lines .level-panic {
+            color: #f14668;
+        }
+        #log-lines .level-warning {
+            color: #ffdd57;
+        }
+        #log-lines .level-debug, #log-lines .level-trace {
+            color: #7a7a7a;
+        }
+        #log-lines mark {
+            background: #ffdd57;
+            color: #0a0a0a;
+        }
     </style>
 </head>
 <body class="has-background-black-bis has-text-light">
     <nav class="navbar is-black" role="navigation" aria-label="main navigation">
         <div class="navbar-brand">
             <a class="navbar-item" href="/">
-                <img src="https://goction.github.io/images/goction.png" alt="Goction Logo" height="28">
+                <img src="`)
+//line internal/api/dashboard/templates/dashboard.qtpl:48
+	qw422016.E().S(assets.Path("goction.png"))
+//line internal/api/dashboard/templates/dashboard.qtpl:48
+	qw422016.N().S(`" alt="Goction Logo" height="28">
                 <strong class="ml-2">Goction Dashboard</strong>
             </a>
             <div class="navbar-item">
                 <span class="tag is-primary">Version: `)
-//line internal/api/dashboard/templates/dashboard.qtpl:39
+//line internal/api/dashboard/templates/dashboard.qtpl:52
 	qw422016.E().S(data.GoctionVersion)
-//line internal/api/dashboard/templates/dashboard.qtpl:39
+//line internal/api/dashboard/templates/dashboard.qtpl:52
 	qw422016.N().S(`</span>
             </div>
         </div>
@@ -73,13 +98,21 @@ func StreamDashboard(qw422016 *qt422016.Writer, data viewmodels.DashboardData) {
                     <div class="buttons">
                         <a class="button is-primary" href="https://goction.github.io" target="_blank">
                             <span class="icon">
-                                <i class="fas fa-book"></i>
+                                `)
+//line internal/api/dashboard/templates/dashboard.qtpl:62
+	streamicon(qw422016, "book")
+//line internal/api/dashboard/templates/dashboard.qtpl:62
+	qw422016.N().S(`
                             </span>
                             <span>Documentation</span>
                         </a>
                         <a class="button is-dark" href="/logout">
                             <span class="icon">
-                                <i class="fas fa-sign-out-alt"></i>
+                                `)
+//line internal/api/dashboard/templates/dashboard.qtpl:68
+	streamicon(qw422016, "sign-out")
+//line internal/api/dashboard/templates/dashboard.qtpl:68
+	qw422016.N().S(`
                             </span>
                             <span>Logout</span>
                         </a>
@@ -96,28 +129,293 @@ func StreamDashboard(qw422016 *qt422016.Writer, data viewmodels.DashboardData) {
                 <div class="box has-background-black-ter">
                     <div class="content has-text-grey-light">
                         <p><strong>Goctions Directory:</strong> `)
-//line internal/api/dashboard/templates/dashboard.qtpl:71
+//line internal/api/dashboard/templates/dashboard.qtpl:84
 	qw422016.E().S(data.Config.GoctionsDir)
-//line internal/api/dashboard/templates/dashboard.qtpl:71
+//line internal/api/dashboard/templates/dashboard.qtpl:84
 	qw422016.N().S(`</p>
                         <p><strong>Port:</strong> `)
-//line internal/api/dashboard/templates/dashboard.qtpl:72
+//line internal/api/dashboard/templates/dashboard.qtpl:85
 	qw422016.N().D(data.Config.Port)
-//line internal/api/dashboard/templates/dashboard.qtpl:72
+//line internal/api/dashboard/templates/dashboard.qtpl:85
 	qw422016.N().S(`</p>
                         <p><strong>Log File:</strong> `)
-//line internal/api/dashboard/templates/dashboard.qtpl:73
+//line internal/api/dashboard/templates/dashboard.qtpl:86
 	qw422016.E().S(data.Config.LogFile)
-//line internal/api/dashboard/templates/dashboard.qtpl:73
+//line internal/api/dashboard/templates/dashboard.qtpl:86
 	qw422016.N().S(`</p>
                         <p><strong>Stats File:</strong> `)
-//line internal/api/dashboard/templates/dashboard.qtpl:74
+//line internal/api/dashboard/templates/dashboard.qtpl:87
 	qw422016.E().S(data.Config.StatsFile)
-//line internal/api/dashboard/templates/dashboard.qtpl:74
+//line internal/api/dashboard/templates/dashboard.qtpl:87
 	qw422016.N().S(`</p>
                     </div>
                 </div>
 
+                <h1 class="title has-text-primary mt-6">System <span class="tag is-dark" id="live-status">Connecting…</span></h1>
+                <div class="box has-background-black-ter">
+                    <div class="chart-summary has-text-grey-light">
+                        <div>CPU<strong id="system-cpu">`)
+//line internal/api/dashboard/templates/dashboard.qtpl:94
+	if data.HasSystem {
+//line internal/api/dashboard/templates/dashboard.qtpl:94
+		qw422016.N().FPrec(data.System.CPUPercent, 1)
+//line internal/api/dashboard/templates/dashboard.qtpl:94
+		qw422016.N().S(`%`)
+//line internal/api/dashboard/templates/dashboard.qtpl:94
+	} else {
+//line internal/api/dashboard/templates/dashboard.qtpl:94
+		qw422016.N().S(`-`)
+//line internal/api/dashboard/templates/dashboard.qtpl:94
+	}
+//line internal/api/dashboard/templates/dashboard.qtpl:94
+	qw422016.N().S(`</strong></div>
+                        <div>Memory<strong id="system-memory">`)
+//line internal/api/dashboard/templates/dashboard.qtpl:95
+	if data.HasSystem {
+//line internal/api/dashboard/templates/dashboard.qtpl:95
+		qw422016.N().FPrec(data.System.MemoryPercent, 1)
+//line internal/api/dashboard/templates/dashboard.qtpl:95
+		qw422016.N().S(`%`)
+//line internal/api/dashboard/templates/dashboard.qtpl:95
+	} else {
+//line internal/api/dashboard/templates/dashboard.qtpl:95
+		qw422016.N().S(`-`)
+//line internal/api/dashboard/templates/dashboard.qtpl:95
+	}
+//line internal/api/dashboard/templates/dashboard.qtpl:95
+	qw422016.N().S(`</strong></div>
+                        <div>Load<strong id="system-load">`)
+//line internal/api/dashboard/templates/dashboard.qtpl:96
+	if data.HasSystem {
+//line internal/api/dashboard/templates/dashboard.qtpl:96
+		qw422016.N().FPrec(data.System.Load1, 2)
+//line internal/api/dashboard/templates/dashboard.qtpl:96
+	} else {
+//line internal/api/dashboard/templates/dashboard.qtpl:96
+		qw422016.N().S(`-`)
+//line internal/api/dashboard/templates/dashboard.qtpl:96
+	}
+//line internal/api/dashboard/templates/dashboard.qtpl:96
+	qw422016.N().S(`</strong></div>
+                        <div>Uptime<strong id="system-uptime">`)
+//line internal/api/dashboard/templates/dashboard.qtpl:97
+	if data.HasSystem {
+//line internal/api/dashboard/templates/dashboard.qtpl:97
+		qw422016.E().S(data.System.Uptime().String())
+//line internal/api/dashboard/templates/dashboard.qtpl:97
+	} else {
+//line internal/api/dashboard/templates/dashboard.qtpl:97
+		qw422016.N().S(`-`)
+//line internal/api/dashboard/templates/dashboard.qtpl:97
+	}
+//line internal/api/dashboard/templates/dashboard.qtpl:97
+	qw422016.N().S(`</strong></div>
+                    </div>
+                </div>
+
+                <h1 class="title has-text-primary mt-6">Goctions</h1>
+                <div class="box has-background-black-ter">
+                    `)
+//line internal/api/dashboard/templates/dashboard.qtpl:103
+	if data.GoctionsError != "" {
+//line internal/api/dashboard/templates/dashboard.qtpl:103
+		qw422016.N().S(`
+                    <p class="has-text-danger">`)
+//line internal/api/dashboard/templates/dashboard.qtpl:104
+		qw422016.E().S(data.GoctionsError)
+//line internal/api/dashboard/templates/dashboard.qtpl:104
+		qw422016.N().S(`</p>
+                    `)
+//line internal/api/dashboard/templates/dashboard.qtpl:105
+	} else {
+//line internal/api/dashboard/templates/dashboard.qtpl:105
+		qw422016.N().S(`
+                    <table class="table is-fullwidth has-background-black-ter has-text-grey-light">
+                        <thead>
+                            <tr>
+                                <th class="has-text-grey-light">Goction</th>
+                                <th class="has-text-grey-light">Description</th>
+                                <th class="has-text-grey-light">Run</th>
+                                <th class="has-text-grey-light"></th>
+                            </tr>
+                        </thead>
+                        <tbody>
+                            `)
+//line internal/api/dashboard/templates/dashboard.qtpl:116
+		for _, goction := range data.Goctions {
+//line internal/api/dashboard/templates/dashboard.qtpl:116
+			qw422016.N().S(`
+                            <tr>
+                                <td>
+                                    `)
+//line internal/api/dashboard/templates/dashboard.qtpl:119
+			qw422016.E().S(goction.Name)
+//line internal/api/dashboard/templates/dashboard.qtpl:119
+			qw422016.N().S(`
+                                    `)
+//line internal/api/dashboard/templates/dashboard.qtpl:120
+			if goction.Disabled {
+//line internal/api/dashboard/templates/dashboard.qtpl:120
+				qw422016.N().S(`<span class="tag is-warning">disabled</span>`)
+//line internal/api/dashboard/templates/dashboard.qtpl:120
+			}
+//line internal/api/dashboard/templates/dashboard.qtpl:120
+			qw422016.N().S(`
+                                </td>
+                                <td>`)
+//line internal/api/dashboard/templates/dashboard.qtpl:122
+			qw422016.E().S(goction.Description)
+//line internal/api/dashboard/templates/dashboard.qtpl:122
+			qw422016.N().S(`</td>
+                                <td>
+                                    `)
+//line internal/api/dashboard/templates/dashboard.qtpl:124
+			if goction.Error != "" {
+//line internal/api/dashboard/templates/dashboard.qtpl:124
+				qw422016.N().S(`
+                                        <span class="has-text-danger">`)
+//line internal/api/dashboard/templates/dashboard.qtpl:125
+				qw422016.E().S(goction.Error)
+//line internal/api/dashboard/templates/dashboard.qtpl:125
+				qw422016.N().S(`</span>
+                                    `)
+//line internal/api/dashboard/templates/dashboard.qtpl:126
+			} else {
+//line internal/api/dashboard/templates/dashboard.qtpl:126
+				qw422016.N().S(`
+                                    <form class="run-form" data-goction="`)
+//line internal/api/dashboard/templates/dashboard.qtpl:127
+				qw422016.E().S(goction.Name)
+//line internal/api/dashboard/templates/dashboard.qtpl:127
+				qw422016.N().S(`" data-declared="`)
+//line internal/api/dashboard/templates/dashboard.qtpl:127
+				if len(goction.Args) > 0 {
+//line internal/api/dashboard/templates/dashboard.qtpl:127
+					qw422016.N().S(`true`)
+//line internal/api/dashboard/templates/dashboard.qtpl:127
+				} else {
+//line internal/api/dashboard/templates/dashboard.qtpl:127
+					qw422016.N().S(`false`)
+//line internal/api/dashboard/templates/dashboard.qtpl:127
+				}
+//line internal/api/dashboard/templates/dashboard.qtpl:127
+				qw422016.N().S(`">
+                                        <div class="field is-grouped is-grouped-multiline">
+                                            `)
+//line internal/api/dashboard/templates/dashboard.qtpl:129
+				for _, arg := range goction.Args {
+//line internal/api/dashboard/templates/dashboard.qtpl:129
+					qw422016.N().S(`
+                                            <div class="control">
+                                                <input class="input is-small" name="arg"
+                                                    `)
+//line internal/api/dashboard/templates/dashboard.qtpl:132
+					if arg.Secret {
+//line internal/api/dashboard/templates/dashboard.qtpl:132
+						qw422016.N().S(`type="password" autocomplete="off"`)
+//line internal/api/dashboard/templates/dashboard.qtpl:132
+					} else {
+//line internal/api/dashboard/templates/dashboard.qtpl:132
+						qw422016.N().S(`type="text"`)
+//line internal/api/dashboard/templates/dashboard.qtpl:132
+					}
+//line internal/api/dashboard/templates/dashboard.qtpl:132
+					qw422016.N().S(`
+                                                    placeholder="`)
+//line internal/api/dashboard/templates/dashboard.qtpl:133
+					qw422016.E().S(arg.Name)
+//line internal/api/dashboard/templates/dashboard.qtpl:133
+					if !arg.Required {
+//line internal/api/dashboard/templates/dashboard.qtpl:133
+						qw422016.N().S(` (optional)`)
+//line internal/api/dashboard/templates/dashboard.qtpl:133
+					}
+//line internal/api/dashboard/templates/dashboard.qtpl:133
+					qw422016.N().S(`"
+                                                    title="`)
+//line internal/api/dashboard/templates/dashboard.qtpl:134
+					qw422016.E().S(arg.Description)
+//line internal/api/dashboard/templates/dashboard.qtpl:134
+					qw422016.N().S(`" aria-label="`)
+//line internal/api/dashboard/templates/dashboard.qtpl:134
+					qw422016.E().S(arg.Name)
+//line internal/api/dashboard/templates/dashboard.qtpl:134
+					qw422016.N().S(`"
+                                                    `)
+//line internal/api/dashboard/templates/dashboard.qtpl:135
+					if arg.Required {
+//line internal/api/dashboard/templates/dashboard.qtpl:135
+						qw422016.N().S(`required`)
+//line internal/api/dashboard/templates/dashboard.qtpl:135
+					}
+//line internal/api/dashboard/templates/dashboard.qtpl:135
+					qw422016.N().S(`>
+                                            </div>
+                                            `)
+//line internal/api/dashboard/templates/dashboard.qtpl:137
+				}
+//line internal/api/dashboard/templates/dashboard.qtpl:137
+				qw422016.N().S(`
+                                            `)
+//line internal/api/dashboard/templates/dashboard.qtpl:138
+				if len(goction.Args) == 0 {
+//line internal/api/dashboard/templates/dashboard.qtpl:138
+					qw422016.N().S(`
+                                            <div class="control">
+                                                <input class="input is-small" type="text" name="arg" placeholder="Arguments, separated by spaces" aria-label="Arguments">
+                                            </div>
+                                            `)
+//line internal/api/dashboard/templates/dashboard.qtpl:142
+				}
+//line internal/api/dashboard/templates/dashboard.qtpl:142
+				qw422016.N().S(`
+                                            <div class="control">
+                                                <button class="button is-small is-primary" type="submit">Run</button>
+                                            </div>
+                                        </div>
+                                        <div class="run-result content is-small" hidden>
+                                            <p>
+                                                <span class="tag run-status"></span>
+                                                <span class="run-duration"></span>
+                                                <a class="has-text-primary run-link">View execution</a>
+                                            </p>
+                                            <pre class="has-background-black-bis has-text-grey-light run-output"></pre>
+                                        </div>
+                                    </form>
+                                    `)
+//line internal/api/dashboard/templates/dashboard.qtpl:156
+			}
+//line internal/api/dashboard/templates/dashboard.qtpl:156
+			qw422016.N().S(`
+                                </td>
+                                <td>
+                                    <a class="has-text-primary" href="/goctions/`)
+//line internal/api/dashboard/templates/dashboard.qtpl:159
+			qw422016.N().U(goction.Name)
+//line internal/api/dashboard/templates/dashboard.qtpl:159
+			qw422016.N().S(`/history">History</a>
+                                    &middot;
+                                    <a class="has-text-primary" href="/goctions/`)
+//line internal/api/dashboard/templates/dashboard.qtpl:161
+			qw422016.N().U(goction.Name)
+//line internal/api/dashboard/templates/dashboard.qtpl:161
+			qw422016.N().S(`/edit">Edit</a>
+                                </td>
+                            </tr>
+                            `)
+//line internal/api/dashboard/templates/dashboard.qtpl:164
+		}
+//line internal/api/dashboard/templates/dashboard.qtpl:164
+		qw422016.N().S(`
+                        </tbody>
+                    </table>
+                    `)
+//line internal/api/dashboard/templates/dashboard.qtpl:167
+	}
+//line internal/api/dashboard/templates/dashboard.qtpl:167
+	qw422016.N().S(`
+                </div>
+
                 <h1 class="title has-text-primary mt-6">Goction Statistics</h1>
                 <div class="box has-background-black-ter">
                     <table class="table is-fullwidth has-background-black-ter has-text-grey-light">
@@ -132,106 +430,271 @@ func StreamDashboard(qw422016 *qt422016.Writer, data viewmodels.DashboardData) {
                                 <th class="has-text-grey-light">Last Executed</th>
                             </tr>
                         </thead>
-                        <tbody>
+                        <tbody id="stats-rows">
                             `)
-//line internal/api/dashboard/templates/dashboard.qtpl:93
+//line internal/api/dashboard/templates/dashboard.qtpl:185
 	for name, stat := range data.Stats {
-//line internal/api/dashboard/templates/dashboard.qtpl:93
+//line internal/api/dashboard/templates/dashboard.qtpl:185
 		qw422016.N().S(`
                             <tr>
                                 <td>`)
-//line internal/api/dashboard/templates/dashboard.qtpl:95
+//line internal/api/dashboard/templates/dashboard.qtpl:187
 		qw422016.E().S(name)
-//line internal/api/dashboard/templates/dashboard.qtpl:95
+//line internal/api/dashboard/templates/dashboard.qtpl:187
 		qw422016.N().S(`</td>
                                 <td>`)
-//line internal/api/dashboard/templates/dashboard.qtpl:96
+//line internal/api/dashboard/templates/dashboard.qtpl:188
 		qw422016.N().D(stat.TotalCalls)
-//line internal/api/dashboard/templates/dashboard.qtpl:96
+//line internal/api/dashboard/templates/dashboard.qtpl:188
 		qw422016.N().S(`</td>
                                 <td>`)
-//line internal/api/dashboard/templates/dashboard.qtpl:97
+//line internal/api/dashboard/templates/dashboard.qtpl:189
 		qw422016.N().D(stat.SuccessfulCalls)
-//line internal/api/dashboard/templates/dashboard.qtpl:97
+//line internal/api/dashboard/templates/dashboard.qtpl:189
 		qw422016.N().S(`</td>
                                 <td>
                                     `)
-//line internal/api/dashboard/templates/dashboard.qtpl:99
+//line internal/api/dashboard/templates/dashboard.qtpl:191
 		if stat.TotalCalls > 0 {
-//line internal/api/dashboard/templates/dashboard.qtpl:99
+//line internal/api/dashboard/templates/dashboard.qtpl:191
 			qw422016.N().S(`
                                         `)
-//line internal/api/dashboard/templates/dashboard.qtpl:100
-			qw422016.N().F(float64(stat.SuccessfulCalls) / float64(stat.TotalCalls) * 100)
-//line internal/api/dashboard/templates/dashboard.qtpl:100
+//line internal/api/dashboard/templates/dashboard.qtpl:192
+			qw422016.N().FPrec(float64(stat.SuccessfulCalls)/float64(stat.TotalCalls)*100, 1)
+//line internal/api/dashboard/templates/dashboard.qtpl:192
 			qw422016.N().S(`%
                                     `)
-//line internal/api/dashboard/templates/dashboard.qtpl:101
+//line internal/api/dashboard/templates/dashboard.qtpl:193
 		} else {
-//line internal/api/dashboard/templates/dashboard.qtpl:101
+//line internal/api/dashboard/templates/dashboard.qtpl:193
 			qw422016.N().S(`
                                         N/A
                                     `)
-//line internal/api/dashboard/templates/dashboard.qtpl:103
+//line internal/api/dashboard/templates/dashboard.qtpl:195
 		}
-//line internal/api/dashboard/templates/dashboard.qtpl:103
+//line internal/api/dashboard/templates/dashboard.qtpl:195
 		qw422016.N().S(`
                                 </td>
                                 <td>`)
-//line internal/api/dashboard/templates/dashboard.qtpl:105
+//line internal/api/dashboard/templates/dashboard.qtpl:197
 		qw422016.E().S(stat.TotalDuration.String())
-//line internal/api/dashboard/templates/dashboard.qtpl:105
+//line internal/api/dashboard/templates/dashboard.qtpl:197
 		qw422016.N().S(`</td>
                                 <td>
                                     `)
-//line internal/api/dashboard/templates/dashboard.qtpl:107
+//line internal/api/dashboard/templates/dashboard.qtpl:199
 		if stat.TotalCalls > 0 {
-//line internal/api/dashboard/templates/dashboard.qtpl:107
+//line internal/api/dashboard/templates/dashboard.qtpl:199
 			qw422016.N().S(`
                                         `)
-//line internal/api/dashboard/templates/dashboard.qtpl:108
+//line internal/api/dashboard/templates/dashboard.qtpl:200
 			qw422016.E().S((stat.TotalDuration / time.Duration(stat.TotalCalls)).String())
-//line internal/api/dashboard/templates/dashboard.qtpl:108
+//line internal/api/dashboard/templates/dashboard.qtpl:200
 			qw422016.N().S(`
                                     `)
-//line internal/api/dashboard/templates/dashboard.qtpl:109
+//line internal/api/dashboard/templates/dashboard.qtpl:201
 		} else {
-//line internal/api/dashboard/templates/dashboard.qtpl:109
+//line internal/api/dashboard/templates/dashboard.qtpl:201
 			qw422016.N().S(`
                                         N/A
                                     `)
-//line internal/api/dashboard/templates/dashboard.qtpl:111
+//line internal/api/dashboard/templates/dashboard.qtpl:203
 		}
-//line internal/api/dashboard/templates/dashboard.qtpl:111
+//line internal/api/dashboard/templates/dashboard.qtpl:203
 		qw422016.N().S(`
                                 </td>
                                 <td>`)
-//line internal/api/dashboard/templates/dashboard.qtpl:113
+//line internal/api/dashboard/templates/dashboard.qtpl:205
 		qw422016.E().S(stat.LastExecuted.Format("2006-01-02 15:04:05"))
-//line internal/api/dashboard/templates/dashboard.qtpl:113
+//line internal/api/dashboard/templates/dashboard.qtpl:205
 		qw422016.N().S(`</td>
                             </tr>
                             `)
-//line internal/api/dashboard/templates/dashboard.qtpl:115
+//line internal/api/dashboard/templates/dashboard.qtpl:207
 	}
-//line internal/api/dashboard/templates/dashboard.qtpl:115
+//line internal/api/dashboard/templates/dashboard.qtpl:207
 	qw422016.N().S(`
                         </tbody>
                     </table>
                 </div>
 
-                `)
-//line internal/api/dashboard/templates/dashboard.qtpl:147
+                <h1 class="title has-text-primary mt-6">Charts</h1>
+                <div class="box has-background-black-ter" id="charts">
+                    <div class="field is-grouped is-grouped-multiline">
+                        <div class="control">
+                            <div class="select is-small">
+                                <select id="chart-goction" aria-label="Goction">
+                                    <option value="">All goctions</option>
+                                    `)
+//line internal/api/dashboard/templates/dashboard.qtpl:219
+	for _, goction := range data.Goctions {
+//line internal/api/dashboard/templates/dashboard.qtpl:219
+		qw422016.N().S(`
+                                    <option value="`)
+//line internal/api/dashboard/templates/dashboard.qtpl:220
+		qw422016.E().S(goction.Name)
+//line internal/api/dashboard/templates/dashboard.qtpl:220
+		qw422016.N().S(`">`)
+//line internal/api/dashboard/templates/dashboard.qtpl:220
+		qw422016.E().S(goction.Name)
+//line internal/api/dashboard/templates/dashboard.qtpl:220
+		qw422016.N().S(`</option>
+                                    `)
+//line internal/api/dashboard/templates/dashboard.qtpl:221
+	}
+//line internal/api/dashboard/templates/dashboard.qtpl:221
 	qw422016.N().S(`
+                                </select>
+                            </div>
+                        </div>
+                        <div class="control">
+                            <div class="tabs is-toggle is-small" id="chart-range">
+                                <ul>
+                                    <li class="is-active"><a data-range="24h">24 hours</a></li>
+                                    <li><a data-range="7d">7 days</a></li>
+                                    <li><a data-range="30d">30 days</a></li>
+                                    <li><a data-range="90d">90 days</a></li>
+                                </ul>
+                            </div>
+                        </div>
+                    </div>
+                    <div class="chart-summary chart-panel has-text-grey-light">
+                        <div>Executions<strong id="chart-total">-</strong></div>
+                        <div>Error rate<strong id="chart-error-rate">-</strong></div>
+                        <div>p50 latency<strong id="chart-p50">-</strong></div>
+                        <div>p99 latency<strong id="chart-p99">-</strong></div>
+                    </div>
+                    <h2 class="subtitle has-text-grey-light">Calls over time</h2>
+                    <div class="chart-panel" id="chart-calls"></div>
+                    <h2 class="subtitle has-text-grey-light">Error rate</h2>
+                    <div class="chart-panel" id="chart-errors"></div>
+                    <h2 class="subtitle has-text-grey-light">Latency percentiles</h2>
+                    <div class="chart-panel" id="chart-latency"></div>
+                    <h2 class="subtitle has-text-grey-light">Executions by time of day</h2>
+                    <div class="chart-panel" id="chart-heatmap"></div>
+                </div>
 
-                <h1 class="title has-text-primary mt-6">Recent Logs</h1>
+                <h1 class="title has-text-primary mt-6">Recent Executions</h1>
                 <div class="box has-background-black-ter">
-                    <div class="content has-text-grey-light log-container">
-                        <pre class="has-background-black-ter has-text-grey-light">`)
-//line internal/api/dashboard/templates/dashboard.qtpl:152
-	qw422016.E().S(strings.Join(viewmodels.Reverse(data.RecentLogs), "\n"))
-//line internal/api/dashboard/templates/dashboard.qtpl:152
-	qw422016.N().S(`</pre>
+                    <table class="table is-fullwidth has-background-black-ter has-text-grey-light">
+                        <thead>
+                            <tr>
+                                <th class="has-text-grey-light">Timestamp</th>
+                                <th class="has-text-grey-light">Goction</th>
+                                <th class="has-text-grey-light">Status</th>
+                                <th class="has-text-grey-light">Duration</th>
+                                <th class="has-text-grey-light">Details</th>
+                            </tr>
+                        </thead>
+                        <tbody id="recent-rows">
+                            `)
+//line internal/api/dashboard/templates/dashboard.qtpl:265
+	for _, record := range data.RecentExecutions {
+//line internal/api/dashboard/templates/dashboard.qtpl:265
+		qw422016.N().S(`
+                            <tr>
+                                <td>`)
+//line internal/api/dashboard/templates/dashboard.qtpl:267
+		qw422016.E().S(record.Timestamp.Format("2006-01-02 15:04:05"))
+//line internal/api/dashboard/templates/dashboard.qtpl:267
+		qw422016.N().S(`</td>
+                                <td>`)
+//line internal/api/dashboard/templates/dashboard.qtpl:268
+		qw422016.E().S(record.Goction)
+//line internal/api/dashboard/templates/dashboard.qtpl:268
+		qw422016.N().S(`</td>
+                                <td>`)
+//line internal/api/dashboard/templates/dashboard.qtpl:269
+		qw422016.E().S(record.Status)
+//line internal/api/dashboard/templates/dashboard.qtpl:269
+		qw422016.N().S(`</td>
+                                <td>`)
+//line internal/api/dashboard/templates/dashboard.qtpl:270
+		qw422016.E().S(record.Duration.String())
+//line internal/api/dashboard/templates/dashboard.qtpl:270
+		qw422016.N().S(`</td>
+                                <td>
+                                    `)
+//line internal/api/dashboard/templates/dashboard.qtpl:272
+		if record.ID != "" {
+//line internal/api/dashboard/templates/dashboard.qtpl:272
+			qw422016.N().S(`
+                                        <a class="has-text-primary" href="/executions/`)
+//line internal/api/dashboard/templates/dashboard.qtpl:273
+			qw422016.N().U(record.ID)
+//line internal/api/dashboard/templates/dashboard.qtpl:273
+			qw422016.N().S(`">View</a>
+                                    `)
+//line internal/api/dashboard/templates/dashboard.qtpl:274
+		}
+//line internal/api/dashboard/templates/dashboard.qtpl:274
+		qw422016.N().S(`
+                                </td>
+                            </tr>
+                            `)
+//line internal/api/dashboard/templates/dashboard.qtpl:277
+	}
+//line internal/api/dashboard/templates/dashboard.qtpl:277
+	qw422016.N().S(`
+                        </tbody>
+                    </table>
+                </div>
+
+                <h1 class="title has-text-primary mt-6">Live Logs</h1>
+                <div class="box has-background-black-ter" id="log-panel">
+                    <div class="field is-grouped is-grouped-multiline">
+                        <div class="control">
+                            <div class="select is-small">
+                                <select id="log-level" aria-label="Level">
+                                    <option value="">All levels</option>
+                                    <option value="error">Error</option>
+                                    <option value="warn">Warning and above</option>
+                                    <option value="info">Info and above</option>
+                                    <option value="debug">Debug and above</option>
+                                </select>
+                            </div>
+                        </div>
+                        <div class="control">
+                            <div class="select is-small">
+                                <select id="log-goction" aria-label="Goction">
+                                    <option value="">All goctions</option>
+                                    `)
+//line internal/api/dashboard/templates/dashboard.qtpl:300
+	for _, goction := range data.Goctions {
+//line internal/api/dashboard/templates/dashboard.qtpl:300
+		qw422016.N().S(`
+                                    <option value="`)
+//line internal/api/dashboard/templates/dashboard.qtpl:301
+		qw422016.E().S(goction.Name)
+//line internal/api/dashboard/templates/dashboard.qtpl:301
+		qw422016.N().S(`">`)
+//line internal/api/dashboard/templates/dashboard.qtpl:301
+		qw422016.E().S(goction.Name)
+//line internal/api/dashboard/templates/dashboard.qtpl:301
+		qw422016.N().S(`</option>
+                                    `)
+//line internal/api/dashboard/templates/dashboard.qtpl:302
+	}
+//line internal/api/dashboard/templates/dashboard.qtpl:302
+	qw422016.N().S(`
+                                </select>
+                            </div>
+                        </div>
+                        <div class="control is-expanded">
+                            <input class="input is-small" type="search" id="log-search" placeholder="Highlight text">
+                        </div>
+                        <div class="control">
+                            <button class="button is-small is-dark" id="log-pause" type="button">Pause</button>
+                        </div>
+                        <div class="control">
+                            <a class="button is-small is-primary" id="log-download" href="/logs/download" download>Download</a>
+                        </div>
+                        <div class="control">
+                            <span class="tag is-dark" id="log-status">Connecting…</span>
+                        </div>
+                    </div>
+                    <div class="content has-text-grey-light log-container" id="log-container">
+                        <pre class="has-background-black-ter has-text-grey-light" id="log-lines"></pre>
                     </div>
                 </div>
             </div>
@@ -246,34 +709,432 @@ func StreamDashboard(qw422016 *qt422016.Writer, data viewmodels.DashboardData) {
             </p>
         </div>
     </footer>
+    <script src="`)
+//line internal/api/dashboard/templates/dashboard.qtpl:335
+	qw422016.E().S(assets.Path("charts.js"))
+//line internal/api/dashboard/templates/dashboard.qtpl:335
+	qw422016.N().S(`"></script>
+    <script>
+    // Live updates: /events pushes the system metrics, the statistics and the executions as they change
+    (function() {
+        var statsRows = document.getElementById("stats-rows");
+        var recentRows = document.getElementById("recent-rows");
+        var status = document.getElementById("live-status");
+        var stats = {};
+        var recent = [];
+        var maxRecent = 10;
+
+        function pad(n) {
+            return n < 10 ? "0" + n : "" + n;
+        }
+
+        function formatTime(value) {
+            var t = new Date(value);
+            return t.getFullYear() + "-" + pad(t.getMonth() + 1) + "-" + pad(t.getDate()) + " " +
+                pad(t.getHours()) + ":" + pad(t.getMinutes()) + ":" + pad(t.getSeconds());
+        }
+
+        // formatDuration formats nanoseconds
+        function formatDuration(ns) {
+            if (ns >= 1e9) {
+                return (ns / 1e9).toFixed(3) + "s";
+            }
+            if (ns >= 1e6) {
+                return (ns / 1e6).toFixed(3) + "ms";
+            }
+            return (ns / 1e3).toFixed(3) + "µs";
+        }
+
+        function formatUptime(seconds) {
+            var days = Math.floor(seconds / 86400);
+            var hours = Math.floor(seconds % 86400 / 3600);
+            var minutes = Math.floor(seconds % 3600 / 60);
+            return (days > 0 ? days + "d" : "") + hours + "h" + pad(minutes) + "m";
+        }
+
+        function cell(row, text) {
+            var td = document.createElement("td");
+            td.textContent = text;
+            row.appendChild(td);
+            return td;
+        }
+
+        function renderStats() {
+            statsRows.innerHTML = "";
+            Object.keys(stats).sort().forEach(function(name) {
+                var stat = stats[name];
+                var row = document.createElement("tr");
+                cell(row, name);
+                cell(row, stat.total_calls);
+                cell(row, stat.successful_calls);
+                cell(row, stat.total_calls > 0 ? (stat.successful_calls / stat.total_calls * 100).toFixed(1) + "%" : "N/A");
+                cell(row, formatDuration(stat.total_duration));
+                cell(row, stat.total_calls > 0 ? formatDuration(stat.total_duration / stat.total_calls) : "N/A");
+                cell(row, formatTime(stat.last_executed));
+                statsRows.appendChild(row);
+            });
+        }
+
+        function renderRecent() {
+            recentRows.innerHTML = "";
+            recent.forEach(function(record) {
+                var row = document.createElement("tr");
+                cell(row, formatTime(record.timestamp));
+                cell(row, record.goction);
+                cell(row, record.status);
+                cell(row, formatDuration(record.duration));
+                var links = cell(row, "");
+                if (record.id) {
+                    var link = document.createElement("a");
+                    link.className = "has-text-primary";
+                    link.href = "/executions/" + encodeURIComponent(record.id);
+                    link.textContent = "View";
+                    links.appendChild(link);
+                }
+                recentRows.appendChild(row);
+            });
+        }
+
+        function renderSystem(sample) {
+            if (!sample) {
+                return;
+            }
+            document.getElementById("system-cpu").textContent = sample.cpu_percent.toFixed(1) + "%";
+            document.getElementById("system-memory").textContent = sample.memory_percent.toFixed(1) + "%";
+            document.getElementById("system-load").textContent = sample.load1.toFixed(2);
+            document.getElementById("system-uptime").textContent = formatUptime(sample.uptime_seconds);
+        }
+
+        var source = new EventSource("/events");
+        source.onopen = function() {
+            status.textContent = "Live";
+        };
+        source.onerror = function() {
+            status.textContent = "Reconnecting…";
+        };
+        source.addEventListener("snapshot", function(event) {
+            var snapshot = JSON.parse(event.data);
+            stats = snapshot.stats || {};
+            recent = snapshot.recent_executions || [];
+            renderSystem(snapshot.system);
+            renderStats();
+            renderRecent();
+        });
+        source.addEventListener("system", function(event) {
+            renderSystem(JSON.parse(event.data));
+        });
+        source.addEventListener("stats", function(event) {
+            var update = JSON.parse(event.data);
+            stats[update.goction] = update.stats;
+            renderStats();
+        });
+        source.addEventListener("execution", function(event) {
+            recent.unshift(JSON.parse(event.data));
+            recent = recent.slice(0, maxRecent);
+            renderRecent();
+        });
+    })();
+
+    // Charts: fed by /stats/timeseries for the selected goction and range, in the browser time zone
+    (function() {
+        var goction = document.getElementById("chart-goction");
+        var ranges = document.getElementById("chart-range");
+        var range = "24h";
+        var series = null;
+        var colors = {success: "#48c78e", failure: "#f14668", timeout: "#ffe08a", p50: "#00d1b2", p90: "#3e8ed0", p99: "#b86bff"};
+
+        function formatMs(ms) {
+            if (ms >= 1000) {
+                return (ms / 1000).toFixed(ms >= 10000 ? 0 : 1) + "s";
+            }
+            return ms >= 10 ? Math.round(ms) + "ms" : ms.toFixed(1) + "ms";
+        }
+
+        function pad(n) {
+            return n < 10 ? "0" + n : "" + n;
+        }
+
+        function labels(data) {
+            return data.buckets.map(function(bucket) {
+                var start = new Date(bucket.start);
+                var day = pad(start.getMonth() + 1) + "-" + pad(start.getDate());
+                var time = pad(start.getHours()) + ":" + pad(start.getMinutes());
+                if (data.bucket_seconds >= 86400) {
+                    return {short: day, long: day};
+                }
+                return {short: data.bucket_seconds >= 21600 ? day + " " + time : time, long: day + " " + time};
+            });
+        }
+
+        function render() {
+            if (!series) {
+                return;
+            }
+            var data = series;
+            var buckets = data.buckets;
+            var summary = data.summary;
+            var pick = function(key) {
+                return buckets.map(function(bucket) { return bucket[key]; });
+            };
+            var latency = function(key) {
+                return buckets.map(function(bucket) { return bucket.total > 0 ? bucket[key] : null; });
+            };
+            var count = function(value) { return String(Math.round(value)); };
+
+            document.getElementById("chart-total").textContent = summary.total;
+            document.getElementById("chart-error-rate").textContent = summary.total > 0 ?
+                ((summary.failure + summary.timeout) / summary.total * 100).toFixed(1) + "%" : "-";
+            document.getElementById("chart-p50").textContent = summary.total > 0 ? formatMs(summary.p50_ms) : "-";
+            document.getElementById("chart-p99").textContent = summary.total > 0 ? formatMs(summary.p99_ms) : "-";
+
+            var points = labels(data);
+            GoctionCharts.bars(document.getElementById("chart-calls"), points, [
+                {name: "Success", color: colors.success, values: pick("success")},
+                {name: "Failure", color: colors.failure, values: pick("failure")},
+                {name: "Timeout", color: colors.timeout, values: pick("timeout")}
+            ], count);
+            GoctionCharts.lines(document.getElementById("chart-errors"), points, [
+                {name: "Failed or timed out", color: colors.failure, values: buckets.map(function(bucket) {
+                    return bucket.total > 0 ? (bucket.failure + bucket.timeout) / bucket.total * 100 : null;
+                })}
+            ], function(value) { return value.toFixed(0) + "%"; });
+            GoctionCharts.lines(document.getElementById("chart-latency"), points, [
+                {name: "p50", color: colors.p50, values: latency("p50_ms")},
+                {name: "p90", color: colors.p90, values: latency("p90_ms")},
+                {name: "p99", color: colors.p99, values: latency("p99_ms")}
+            ], formatMs);
+            GoctionCharts.heatmap(document.getElementById("chart-heatmap"), data.heatmap, colors.p50);
+        }
+
+        function load() {
+            var params = new URLSearchParams({range: range, utc_offset: -new Date().getTimezoneOffset()});
+            if (goction.value) {
+                params.set("goction", goction.value);
+            }
+            fetch("/stats/timeseries?" + params.toString(), {credentials: "same-origin"}).then(function(response) {
+                if (!response.ok) {
+                    throw new Error("Failed to load statistics");
+                }
+                return response.json();
+            }).then(function(data) {
+                series = data;
+                render();
+            }).catch(function(err) {
+                document.getElementById("chart-calls").textContent = err.message;
+            });
+        }
+
+        Array.prototype.forEach.call(ranges.querySelectorAll("a"), function(link) {
+            link.addEventListener("click", function() {
+                range = link.dataset.range;
+                Array.prototype.forEach.call(ranges.querySelectorAll("li"), function(item) {
+                    item.classList.toggle("is-active", item === link.parentNode);
+                });
+                load();
+            });
+        });
+        goction.addEventListener("change", load);
+        var resizing = null;
+        window.addEventListener("resize", function() {
+            clearTimeout(resizing);
+            resizing = setTimeout(render, 200);
+        });
+        load();
+    })();
+
+    // Run forms: each goction is executed through /goctions/{name}/run and its result shown under the form
+    (function() {
+        var statusClasses = {success: "is-success", failure: "is-danger", timeout: "is-warning"};
+
+        function args(form) {
+            var inputs = Array.prototype.map.call(form.querySelectorAll("input[name=arg]"), function(input) {
+                return input.value;
+            });
+            if (form.dataset.declared !== "true") {
+                return inputs[0].split(/\s+/).filter(function(arg) { return arg !== ""; });
+            }
+            // Empty optional arguments at the end are left out
+            while (inputs.length > 0 && inputs[inputs.length - 1] === "") {
+                inputs.pop();
+            }
+            return inputs;
+        }
+
+        function show(form, result) {
+            var box = form.querySelector(".run-result");
+            var status = box.querySelector(".run-status");
+            var link = box.querySelector(".run-link");
+            status.className = "tag run-status " + (statusClasses[result.status] || "is-danger");
+            status.textContent = result.status || "error";
+            box.querySelector(".run-duration").textContent = result.duration || "";
+            box.querySelector(".run-output").textContent = result.error ? result.error : result.result;
+            link.hidden = !result.execution_id;
+            if (result.execution_id) {
+                link.href = "/executions/" + encodeURIComponent(result.execution_id);
+            }
+            box.hidden = false;
+        }
+
+        Array.prototype.forEach.call(document.querySelectorAll(".run-form"), function(form) {
+            form.addEventListener("submit", function(event) {
+                event.preventDefault();
+                var button = form.querySelector("button");
+                button.classList.add("is-loading");
+                fetch("/goctions/" + encodeURIComponent(form.dataset.goction) + "/run", {
+                    method: "POST",
+                    headers: {"Content-Type": "application/json"},
+                    credentials: "same-origin",
+                    body: JSON.stringify({args: args(form)})
+                }).then(function(response) {
+                    if (response.redirected) {
+                        throw new Error("Session expired, please log in again");
+                    }
+                    return response.json();
+                }).then(function(result) {
+                    show(form, result);
+                }).catch(function(err) {
+                    show(form, {error: err.message});
+                }).finally(function() {
+                    button.classList.remove("is-loading");
+                });
+            });
+        });
+    })();
+
+    // Live log panel: entries are streamed from /logs/stream as server-sent events
+    (function() {
+        var maxLines = 1000;
+        var lines = document.getElementById("log-lines");
+        var container = document.getElementById("log-container");
+        var level = document.getElementById("log-level");
+        var goction = document.getElementById("log-goction");
+        var search = document.getElementById("log-search");
+        var pause = document.getElementById("log-pause");
+        var download = document.getElementById("log-download");
+        var status = document.getElementById("log-status");
+        var source = null;
+        var paused = false;
+        var pending = [];
+
+        function escapeHTML(text) {
+            return text.replace(/&/g, "&amp;").replace(/</g, "&lt;").replace(/>/g, "&gt;");
+        }
+
+        function escapeRegExp(text) {
+            return text.replace(/[.*+?^${}()|[\]\\]/g, "\\$&");
+        }
+
+        function highlight(text) {
+            var term = search.value;
+            if (!term) {
+                return escapeHTML(text);
+            }
+            return text.split(new RegExp("(" + escapeRegExp(term) + ")", "i")).map(function(part, i) {
+                return i % 2 === 1 ? "<mark>" + escapeHTML(part) + "</mark>" : escapeHTML(part);
+            }).join("");
+        }
+
+        function render(line) {
+            line.innerHTML = highlight(line.dataset.raw);
+        }
+
+        function append(entry) {
+            var atBottom = container.scrollTop + container.clientHeight >= container.scrollHeight - 5;
+            var line = document.createElement("div");
+            line.className = "level-" + (entry.level || "none");
+            line.dataset.raw = entry.raw;
+            render(line);
+            lines.appendChild(line);
+            while (lines.childNodes.length > maxLines) {
+                lines.removeChild(lines.firstChild);
+            }
+            if (atBottom) {
+                container.scrollTop = container.scrollHeight;
+            }
+        }
+
+        function query() {
+            var params = new URLSearchParams();
+            if (level.value) {
+                params.set("level", level.value);
+            }
+            if (goction.value) {
+                params.set("goction", goction.value);
+            }
+            var encoded = params.toString();
+            return encoded ? "?" + encoded : "";
+        }
+
+        function connect() {
+            if (source) {
+                source.close();
+            }
+            lines.innerHTML = "";
+            pending = [];
+            download.href = "/logs/download" + query();
+            status.textContent = "Connecting…";
+            source = new EventSource("/logs/stream" + query());
+            source.onopen = function() {
+                status.textContent = paused ? "Paused" : "Live";
+            };
+            source.onmessage = function(event) {
+                var entry = JSON.parse(event.data);
+                if (paused) {
+                    pending.push(entry);
+                } else {
+                    append(entry);
+                }
+            };
+            source.onerror = function() {
+                status.textContent = "Reconnecting…";
+            };
+        }
+
+        pause.addEventListener("click", function() {
+            paused = !paused;
+            pause.textContent = paused ? "Resume" : "Pause";
+            status.textContent = paused ? "Paused" : "Live";
+            if (!paused) {
+                pending.forEach(append);
+                pending = [];
+            }
+        });
+        search.addEventListener("input", function() {
+            Array.prototype.forEach.call(lines.childNodes, render);
+        });
+        level.addEventListener("change", connect);
+        goction.addEventListener("change", connect);
+        connect();
+    })();
+    </script>
 </body>
 </html>
 `)
-//line internal/api/dashboard/templates/dashboard.qtpl:169
+//line internal/api/dashboard/templates/dashboard.qtpl:731
 }
 
-//line internal/api/dashboard/templates/dashboard.qtpl:169
+//line internal/api/dashboard/templates/dashboard.qtpl:731
 func WriteDashboard(qq422016 qtio422016.Writer, data viewmodels.DashboardData) {
-//line internal/api/dashboard/templates/dashboard.qtpl:169
+//line internal/api/dashboard/templates/dashboard.qtpl:731
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line internal/api/dashboard/templates/dashboard.qtpl:169
+//line internal/api/dashboard/templates/dashboard.qtpl:731
 	StreamDashboard(qw422016, data)
-//line internal/api/dashboard/templates/dashboard.qtpl:169
+//line internal/api/dashboard/templates/dashboard.qtpl:731
 	qt422016.ReleaseWriter(qw422016)
-//line internal/api/dashboard/templates/dashboard.qtpl:169
+//line internal/api/dashboard/templates/dashboard.qtpl:731
 }
 
-//line internal/api/dashboard/templates/dashboard.qtpl:169
+//line internal/api/dashboard/templates/dashboard.qtpl:731
 func Dashboard(data viewmodels.DashboardData) string {
-//line internal/api/dashboard/templates/dashboard.qtpl:169
+//line internal/api/dashboard/templates/dashboard.qtpl:731
 	qb422016 := qt422016.AcquireByteBuffer()
-//line internal/api/dashboard/templates/dashboard.qtpl:169
+//line internal/api/dashboard/templates/dashboard.qtpl:731
 	WriteDashboard(qb422016, data)
-//line internal/api/dashboard/templates/dashboard.qtpl:169
+//line internal/api/dashboard/templates/dashboard.qtpl:731
 	qs422016 := string(qb422016.B)
-//line internal/api/dashboard/templates/dashboard.qtpl:169
+//line internal/api/dashboard/templates/dashboard.qtpl:731
 	qt422016.ReleaseByteBuffer(qb422016)
-//line internal/api/dashboard/templates/dashboard.qtpl:169
+//line internal/api/dashboard/templates/dashboard.qtpl:731
 	return qs422016
-//line internal/api/dashboard/templates/dashboard.qtpl:169
+//line internal/api/dashboard/templates/dashboard.qtpl:731
 }
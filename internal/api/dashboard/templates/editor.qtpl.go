@@ -0,0 +1,683 @@
+// Code generated by qtc from "editor.qtpl". DO NOT EDIT.
+// See https://github.com/valyala/quicktemplate for details.
+
+//line internal/api/dashboard/templates/editor.qtpl:1
+package templates
+
+//line internal/api/dashboard/templates/editor.qtpl:1
+import (
+	"goction/internal/api/dashboard/assets"
+	"goction/internal/viewmodels"
+)
+
+//line internal/api/dashboard/templates/editor.qtpl:6
+import (
+	qtio422016 "io"
+
+	qt422016 "github.com/valyala/quicktemplate"
+)
+
+//line internal/api/dashboard/templates/editor.qtpl:6
+var (
+	_ = qtio422016.Copy
+	_ = qt422016.AcquireByteBuffer
+)
+
+//line internal/api/dashboard/templates/editor.qtpl:6
+func StreamEditor(qw422016 *qt422016.Writer, data viewmodels.EditorData) {
+//line internal/api/dashboard/templates/editor.qtpl:6
+	qw422016.N().S(`
+<!DOCTYPE html>
+<html lang="en" class="has-background-black-bis">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Goction - Edit `)
+//line internal/api/dashboard/templates/editor.qtpl:12
+	qw422016.E().S(data.Goction)
+//line internal/api/dashboard/templates/editor.qtpl:12
+	qw422016.N().S(`</title>
+    <link rel="stylesheet" href="`)
+//line internal/api/dashboard/templates/editor.qtpl:13
+	qw422016.E().S(assets.Path("dashboard.css"))
+//line internal/api/dashboard/templates/editor.qtpl:13
+	qw422016.N().S(`">
+    <link rel="icon" type="image/png" href="`)
+//line internal/api/dashboard/templates/editor.qtpl:14
+	qw422016.E().S(assets.Path("goction.png"))
+//line internal/api/dashboard/templates/editor.qtpl:14
+	qw422016.N().S(`">
+    <style>
+        .build-errors a {
+            font-family: var(--family-monospace);
+        }
+        .build-output {
+            max-height: 16rem;
+            overflow: auto;
+            white-space: pre-wrap;
+        }
+    </style>
+</head>
+<body class="has-background-black-bis has-text-light">
+    <nav class="navbar is-black" role="navigation" aria-label="main navigation">
+        <div class="navbar-brand">
+            <a class="navbar-item" href="/">
+                <img src="`)
+//line internal/api/dashboard/templates/editor.qtpl:30
+	qw422016.E().S(assets.Path("goction.png"))
+//line internal/api/dashboard/templates/editor.qtpl:30
+	qw422016.N().S(`" alt="Goction Logo" height="28">
+                <strong class="ml-2">Goction Dashboard</strong>
+            </a>
+            <div class="navbar-item">
+                <span class="tag is-primary">Version: `)
+//line internal/api/dashboard/templates/editor.qtpl:34
+	qw422016.E().S(data.GoctionVersion)
+//line internal/api/dashboard/templates/editor.qtpl:34
+	qw422016.N().S(`</span>
+            </div>
+        </div>
+    </nav>
+
+    <main>
+        <section class="section">
+            <div class="container" id="editor" data-goction="`)
+//line internal/api/dashboard/templates/editor.qtpl:41
+	qw422016.E().S(data.Goction)
+//line internal/api/dashboard/templates/editor.qtpl:41
+	qw422016.N().S(`">
+                <h1 class="title has-text-primary">Edit `)
+//line internal/api/dashboard/templates/editor.qtpl:42
+	qw422016.E().S(data.Goction)
+//line internal/api/dashboard/templates/editor.qtpl:42
+	qw422016.N().S(`</h1>
+                <p class="subtitle has-text-grey-light">`)
+//line internal/api/dashboard/templates/editor.qtpl:43
+	qw422016.E().S(data.Description)
+//line internal/api/dashboard/templates/editor.qtpl:43
+	qw422016.N().S(`</p>
+
+                `)
+//line internal/api/dashboard/templates/editor.qtpl:45
+	if data.Error != "" {
+//line internal/api/dashboard/templates/editor.qtpl:45
+		qw422016.N().S(`
+                <div class="box has-background-black-ter">
+                    <p class="has-text-danger">`)
+//line internal/api/dashboard/templates/editor.qtpl:47
+		qw422016.E().S(data.Error)
+//line internal/api/dashboard/templates/editor.qtpl:47
+		qw422016.N().S(`</p>
+                </div>
+                `)
+//line internal/api/dashboard/templates/editor.qtpl:49
+	}
+//line internal/api/dashboard/templates/editor.qtpl:49
+	qw422016.N().S(`
+                `)
+//line internal/api/dashboard/templates/editor.qtpl:50
+	if data.PluginMode {
+//line internal/api/dashboard/templates/editor.qtpl:50
+		qw422016.N().S(`
+                <p class="has-text-warning mb-5">
+                    The server runs goctions in the plugin execution mode, in which it keeps running the first build it loaded:
+                    restart it to run a new build.
+                </p>
+                `)
+//line internal/api/dashboard/templates/editor.qtpl:55
+	}
+//line internal/api/dashboard/templates/editor.qtpl:55
+	qw422016.N().S(`
+
+                <div class="box has-background-black-ter">
+                    <div class="tabs is-toggle is-small">
+                        <ul id="file-tabs">
+                            `)
+//line internal/api/dashboard/templates/editor.qtpl:60
+	for i, file := range data.Files {
+//line internal/api/dashboard/templates/editor.qtpl:60
+		qw422016.N().S(`
+                            <li`)
+//line internal/api/dashboard/templates/editor.qtpl:61
+		if i == 0 {
+//line internal/api/dashboard/templates/editor.qtpl:61
+			qw422016.N().S(` class="is-active"`)
+//line internal/api/dashboard/templates/editor.qtpl:61
+		}
+//line internal/api/dashboard/templates/editor.qtpl:61
+		qw422016.N().S(`><a href="#" data-file="`)
+//line internal/api/dashboard/templates/editor.qtpl:61
+		qw422016.E().S(file.Name)
+//line internal/api/dashboard/templates/editor.qtpl:61
+		qw422016.N().S(`">`)
+//line internal/api/dashboard/templates/editor.qtpl:61
+		qw422016.E().S(file.Name)
+//line internal/api/dashboard/templates/editor.qtpl:61
+		qw422016.N().S(`</a></li>
+                            `)
+//line internal/api/dashboard/templates/editor.qtpl:62
+	}
+//line internal/api/dashboard/templates/editor.qtpl:62
+	qw422016.N().S(`
+                        </ul>
+                    </div>
+                    `)
+//line internal/api/dashboard/templates/editor.qtpl:65
+	for _, file := range data.Files {
+//line internal/api/dashboard/templates/editor.qtpl:65
+		qw422016.N().S(`
+                    <textarea class="source-file" data-name="`)
+//line internal/api/dashboard/templates/editor.qtpl:66
+		qw422016.E().S(file.Name)
+//line internal/api/dashboard/templates/editor.qtpl:66
+		qw422016.N().S(`" aria-label="`)
+//line internal/api/dashboard/templates/editor.qtpl:66
+		qw422016.E().S(file.Name)
+//line internal/api/dashboard/templates/editor.qtpl:66
+		qw422016.N().S(`" hidden>
+`)
+//line internal/api/dashboard/templates/editor.qtpl:67
+		qw422016.E().S(file.Content)
+//line internal/api/dashboard/templates/editor.qtpl:67
+		qw422016.N().S(`</textarea>
+                    `)
+//line internal/api/dashboard/templates/editor.qtpl:68
+	}
+//line internal/api/dashboard/templates/editor.qtpl:68
+	qw422016.N().S(`
+
+                    <div class="field is-grouped mt-4">
+                        <div class="control">
+                            <button class="button is-primary" type="button" id="save" title="Ctrl+S">Save and build</button>
+                        </div>
+                        <div class="control">
+                            <span class="has-text-grey-light" id="save-status"></span>
+                        </div>
+                    </div>
+
+                    <div id="build" hidden>
+                        <h2 class="subtitle has-text-primary">
+                            Build <span class="tag" id="build-status"></span>
+                            <span class="has-text-grey" id="build-duration"></span>
+                        </h2>
+                        <ul class="build-errors mb-5" id="build-errors"></ul>
+                        <pre class="build-output has-background-black-bis has-text-grey-light" id="build-output"></pre>
+                    </div>
+                </div>
+
+                <h2 class="title is-4 has-text-primary mt-6">Test run</h2>
+                <div class="box has-background-black-ter">
+                    <form id="test-run" data-declared="`)
+//line internal/api/dashboard/templates/editor.qtpl:91
+	if len(data.Args) > 0 {
+//line internal/api/dashboard/templates/editor.qtpl:91
+		qw422016.N().S(`true`)
+//line internal/api/dashboard/templates/editor.qtpl:91
+	} else {
+//line internal/api/dashboard/templates/editor.qtpl:91
+		qw422016.N().S(`false`)
+//line internal/api/dashboard/templates/editor.qtpl:91
+	}
+//line internal/api/dashboard/templates/editor.qtpl:91
+	qw422016.N().S(`">
+                        <div class="field is-grouped is-grouped-multiline">
+                            `)
+//line internal/api/dashboard/templates/editor.qtpl:93
+	for _, arg := range data.Args {
+//line internal/api/dashboard/templates/editor.qtpl:93
+		qw422016.N().S(`
+                            <div class="control">
+                                <input class="input is-small" name="arg"
+                                    `)
+//line internal/api/dashboard/templates/editor.qtpl:96
+		if arg.Secret {
+//line internal/api/dashboard/templates/editor.qtpl:96
+			qw422016.N().S(`type="password" autocomplete="off"`)
+//line internal/api/dashboard/templates/editor.qtpl:96
+		} else {
+//line internal/api/dashboard/templates/editor.qtpl:96
+			qw422016.N().S(`type="text"`)
+//line internal/api/dashboard/templates/editor.qtpl:96
+		}
+//line internal/api/dashboard/templates/editor.qtpl:96
+		qw422016.N().S(`
+                                    placeholder="`)
+//line internal/api/dashboard/templates/editor.qtpl:97
+		qw422016.E().S(arg.Name)
+//line internal/api/dashboard/templates/editor.qtpl:97
+		if !arg.Required {
+//line internal/api/dashboard/templates/editor.qtpl:97
+			qw422016.N().S(` (optional)`)
+//line internal/api/dashboard/templates/editor.qtpl:97
+		}
+//line internal/api/dashboard/templates/editor.qtpl:97
+		qw422016.N().S(`"
+                                    title="`)
+//line internal/api/dashboard/templates/editor.qtpl:98
+		qw422016.E().S(arg.Description)
+//line internal/api/dashboard/templates/editor.qtpl:98
+		qw422016.N().S(`" aria-label="`)
+//line internal/api/dashboard/templates/editor.qtpl:98
+		qw422016.E().S(arg.Name)
+//line internal/api/dashboard/templates/editor.qtpl:98
+		qw422016.N().S(`"
+                                    `)
+//line internal/api/dashboard/templates/editor.qtpl:99
+		if arg.Required {
+//line internal/api/dashboard/templates/editor.qtpl:99
+			qw422016.N().S(`required`)
+//line internal/api/dashboard/templates/editor.qtpl:99
+		}
+//line internal/api/dashboard/templates/editor.qtpl:99
+		qw422016.N().S(`>
+                            </div>
+                            `)
+//line internal/api/dashboard/templates/editor.qtpl:101
+	}
+//line internal/api/dashboard/templates/editor.qtpl:101
+	qw422016.N().S(`
+                            `)
+//line internal/api/dashboard/templates/editor.qtpl:102
+	if len(data.Args) == 0 {
+//line internal/api/dashboard/templates/editor.qtpl:102
+		qw422016.N().S(`
+                            <div class="control">
+                                <input class="input is-small" type="text" name="arg" placeholder="Arguments, separated by spaces" aria-label="Arguments">
+                            </div>
+                            `)
+//line internal/api/dashboard/templates/editor.qtpl:106
+	}
+//line internal/api/dashboard/templates/editor.qtpl:106
+	qw422016.N().S(`
+                            <div class="control">
+                                <button class="button is-small is-primary" type="submit">Run the last build</button>
+                            </div>
+                        </div>
+                        <div class="content is-small" id="test-result" hidden>
+                            <p>
+                                <span class="tag" id="test-status"></span>
+                                <span id="test-duration"></span>
+                                <a class="has-text-primary" id="test-link">View execution</a>
+                            </p>
+                            <pre class="has-background-black-bis has-text-grey-light" id="test-output"></pre>
+                        </div>
+                    </form>
+                </div>
+
+                <h2 class="title is-4 has-text-primary mt-6">Revisions</h2>
+                <div class="box has-background-black-ter">
+                    <p class="has-text-grey-light" id="no-revisions"`)
+//line internal/api/dashboard/templates/editor.qtpl:124
+	if len(data.Revisions) > 0 {
+//line internal/api/dashboard/templates/editor.qtpl:124
+		qw422016.N().S(` hidden`)
+//line internal/api/dashboard/templates/editor.qtpl:124
+	}
+//line internal/api/dashboard/templates/editor.qtpl:124
+	qw422016.N().S(`>No revision has been saved from the dashboard yet.</p>
+                    <table class="table is-fullwidth has-background-black-ter has-text-grey-light"`)
+//line internal/api/dashboard/templates/editor.qtpl:125
+	if len(data.Revisions) == 0 {
+//line internal/api/dashboard/templates/editor.qtpl:125
+		qw422016.N().S(` hidden`)
+//line internal/api/dashboard/templates/editor.qtpl:125
+	}
+//line internal/api/dashboard/templates/editor.qtpl:125
+	qw422016.N().S(`>
+                        <thead>
+                            <tr>
+                                <th class="has-text-grey-light">Saved (UTC)</th>
+                                <th class="has-text-grey-light">Author</th>
+                                <th class="has-text-grey-light">Build</th>
+                                <th class="has-text-grey-light"></th>
+                            </tr>
+                        </thead>
+                        <tbody id="revisions">
+                            `)
+//line internal/api/dashboard/templates/editor.qtpl:135
+	for _, rev := range data.Revisions {
+//line internal/api/dashboard/templates/editor.qtpl:135
+		qw422016.N().S(`
+                            <tr>
+                                <td>
+                                    `)
+//line internal/api/dashboard/templates/editor.qtpl:138
+		qw422016.E().S(rev.Time.Format("2006-01-02 15:04:05"))
+//line internal/api/dashboard/templates/editor.qtpl:138
+		qw422016.N().S(`
+                                    `)
+//line internal/api/dashboard/templates/editor.qtpl:139
+		if rev.RestoredFrom != "" {
+//line internal/api/dashboard/templates/editor.qtpl:139
+			qw422016.N().S(`<span class="has-text-grey">(restored `)
+//line internal/api/dashboard/templates/editor.qtpl:139
+			qw422016.E().S(rev.RestoredFrom)
+//line internal/api/dashboard/templates/editor.qtpl:139
+			qw422016.N().S(`)</span>`)
+//line internal/api/dashboard/templates/editor.qtpl:139
+		}
+//line internal/api/dashboard/templates/editor.qtpl:139
+		qw422016.N().S(`
+                                </td>
+                                <td>`)
+//line internal/api/dashboard/templates/editor.qtpl:141
+		qw422016.E().S(rev.Author)
+//line internal/api/dashboard/templates/editor.qtpl:141
+		qw422016.N().S(`</td>
+                                <td>`)
+//line internal/api/dashboard/templates/editor.qtpl:142
+		if rev.Built {
+//line internal/api/dashboard/templates/editor.qtpl:142
+			qw422016.N().S(`<span class="tag is-success">success</span>`)
+//line internal/api/dashboard/templates/editor.qtpl:142
+		} else {
+//line internal/api/dashboard/templates/editor.qtpl:142
+			qw422016.N().S(`<span class="tag is-danger">failure</span>`)
+//line internal/api/dashboard/templates/editor.qtpl:142
+		}
+//line internal/api/dashboard/templates/editor.qtpl:142
+		qw422016.N().S(`</td>
+                                <td><button class="button is-small is-dark restore" type="button" data-revision="`)
+//line internal/api/dashboard/templates/editor.qtpl:143
+		qw422016.E().S(rev.ID)
+//line internal/api/dashboard/templates/editor.qtpl:143
+		qw422016.N().S(`">Restore</button></td>
+                            </tr>
+                            `)
+//line internal/api/dashboard/templates/editor.qtpl:145
+	}
+//line internal/api/dashboard/templates/editor.qtpl:145
+	qw422016.N().S(`
+                        </tbody>
+                    </table>
+                </div>
+
+                <a class="button is-dark" href="/">
+                    <span class="icon">`)
+//line internal/api/dashboard/templates/editor.qtpl:151
+	streamicon(qw422016, "arrow-left")
+//line internal/api/dashboard/templates/editor.qtpl:151
+	qw422016.N().S(`</span>
+                    <span>Back to the dashboard</span>
+                </a>
+            </div>
+        </section>
+    </main>
+
+    <script src="`)
+//line internal/api/dashboard/templates/editor.qtpl:158
+	qw422016.E().S(assets.Path("editor.js"))
+//line internal/api/dashboard/templates/editor.qtpl:158
+	qw422016.N().S(`"></script>
+    <script>
+    (function() {
+        var root = document.getElementById("editor");
+        var goction = root.dataset.goction;
+        var tabs = document.getElementById("file-tabs");
+        var saveButton = document.getElementById("save");
+        var saveStatus = document.getElementById("save-status");
+        var editors = {};
+        var saved = {};
+        var reloading = false;
+
+        function request(path, body) {
+            return fetch("/goctions/" + encodeURIComponent(goction) + path, {
+                method: "POST",
+                headers: {"Content-Type": "application/json"},
+                credentials: "same-origin",
+                body: JSON.stringify(body)
+            }).then(function(response) {
+                if (response.redirected) {
+                    throw new Error("Session expired, please log in again");
+                }
+                return response.json();
+            });
+        }
+
+        // Editors: one per source file, the tabs switch between them
+        Array.prototype.forEach.call(document.querySelectorAll(".source-file"), function(textarea, i) {
+            var name = textarea.dataset.name;
+            textarea.hidden = false;
+            editors[name] = GoctionEditor.create(textarea, name);
+            editors[name].element.hidden = i > 0;
+            saved[name] = editors[name].getValue();
+            textarea.addEventListener("input", updateTabs);
+        });
+
+        function show(name) {
+            Object.keys(editors).forEach(function(other) {
+                editors[other].element.hidden = other !== name;
+            });
+            Array.prototype.forEach.call(tabs.querySelectorAll("a"), function(tab) {
+                tab.parentNode.classList.toggle("is-active", tab.dataset.file === name);
+            });
+        }
+
+        function modified() {
+            return Object.keys(editors).filter(function(name) {
+                return editors[name].getValue() !== saved[name];
+            });
+        }
+
+        function updateTabs() {
+            var changed = modified();
+            Array.prototype.forEach.call(tabs.querySelectorAll("a"), function(tab) {
+                tab.textContent = tab.dataset.file + (changed.indexOf(tab.dataset.file) >= 0 ? " *" : "");
+            });
+        }
+
+        tabs.addEventListener("click", function(event) {
+            if (event.target.dataset.file) {
+                event.preventDefault();
+                show(event.target.dataset.file);
+            }
+        });
+
+        window.addEventListener("beforeunload", function(event) {
+            if (!reloading && modified().length > 0) {
+                event.preventDefault();
+                event.returnValue = "";
+            }
+        });
+
+        // Build: the status, the compiler errors linked to their line and the output
+        function showBuild(build) {
+            var errors = document.getElementById("build-errors");
+            var status = document.getElementById("build-status");
+            status.className = "tag " + (build.success ? "is-success" : "is-danger");
+            status.textContent = build.success ? "success" : "failure";
+            document.getElementById("build-duration").textContent = (build.duration / 1e9).toFixed(1) + "s";
+            document.getElementById("build-output").textContent = build.output || "No output.";
+
+            var markers = {};
+            errors.innerHTML = "";
+            (build.errors || []).forEach(function(error) {
+                (markers[error.file] = markers[error.file] || []).push(error);
+                var item = document.createElement("li");
+                var link = document.createElement("a");
+                link.href = "#";
+                link.className = "has-text-danger";
+                link.textContent = error.file + ":" + error.line + (error.column ? ":" + error.column : "");
+                link.addEventListener("click", function(event) {
+                    event.preventDefault();
+                    if (editors[error.file]) {
+                        show(error.file);
+                        editors[error.file].goTo(error.line, error.column);
+                    }
+                });
+                item.appendChild(link);
+                item.appendChild(document.createTextNode(" " + error.message));
+                errors.appendChild(item);
+            });
+            Object.keys(editors).forEach(function(name) {
+                editors[name].setMarkers(markers[name]);
+            });
+            document.getElementById("build").hidden = false;
+        }
+
+        // Revisions: a save or a restore adds one at the top
+        function addRevision(revision) {
+            var row = document.createElement("tr");
+            var when = document.createElement("td");
+            when.textContent = revision.time.replace("T", " ").slice(0, 19) + " ";
+            if (revision.restored_from) {
+                var from = document.createElement("span");
+                from.className = "has-text-grey";
+                from.textContent = "(restored " + revision.restored_from + ")";
+                when.appendChild(from);
+            }
+            var author = document.createElement("td");
+            author.textContent = revision.author || "";
+            var build = document.createElement("td");
+            build.innerHTML = revision.built ? "<span class=\"tag is-success\">success</span>" : "<span class=\"tag is-danger\">failure</span>";
+            var action = document.createElement("td");
+            var button = document.createElement("button");
+            button.className = "button is-small is-dark restore";
+            button.type = "button";
+            button.dataset.revision = revision.id;
+            button.textContent = "Restore";
+            action.appendChild(button);
+            [when, author, build, action].forEach(function(cell) { row.appendChild(cell); });
+
+            var list = document.getElementById("revisions");
+            list.insertBefore(row, list.firstChild);
+            list.parentNode.hidden = false;
+            document.getElementById("no-revisions").hidden = true;
+        }
+
+        // done applies the outcome of a save or a restore: the files of the revision become the saved state
+        function done(result) {
+            if (result.revision) {
+                var names = result.revision.files.map(function(file) { return file.name; });
+                if (names.sort().join("/") !== Object.keys(editors).sort().join("/")) {
+                    // The restored revision has other files: reload the page to edit them
+                    reloading = true;
+                    window.location.reload();
+                    return;
+                }
+                result.revision.files.forEach(function(file) {
+                    if (editors[file.name].getValue() !== file.content) {
+                        editors[file.name].setValue(file.content);
+                    }
+                    saved[file.name] = file.content;
+                });
+                updateTabs();
+                addRevision(result.revision);
+            }
+            if (result.build) {
+                showBuild(result.build);
+            }
+            saveStatus.textContent = result.error || (result.build && result.build.success ? "Saved and built" : "Saved, but the build failed");
+        }
+
+        function failed(err) {
+            saveStatus.textContent = err.message;
+        }
+
+        function save() {
+            saveButton.classList.add("is-loading");
+            saveStatus.textContent = "";
+            var files = Object.keys(editors).map(function(name) {
+                return {name: name, content: editors[name].getValue()};
+            });
+            request("/source", {files: files}).then(done).catch(failed).finally(function() {
+                saveButton.classList.remove("is-loading");
+            });
+        }
+
+        saveButton.addEventListener("click", save);
+        document.addEventListener("keydown", function(event) {
+            if ((event.ctrlKey || event.metaKey) && event.key === "s") {
+                event.preventDefault();
+                save();
+            }
+        });
+
+        document.getElementById("revisions").addEventListener("click", function(event) {
+            var button = event.target.closest(".restore");
+            if (!button) {
+                return;
+            }
+            var message = "Restore the files of this revision and build them?";
+            if (modified().length > 0) {
+                message += " Unsaved changes will be lost.";
+            }
+            if (!window.confirm(message)) {
+                return;
+            }
+            button.classList.add("is-loading");
+            saveStatus.textContent = "";
+            request("/revisions/" + encodeURIComponent(button.dataset.revision) + "/restore", {}).then(done).catch(failed).finally(function() {
+                button.classList.remove("is-loading");
+            });
+        });
+
+        // Test run: the goction runs through the same path as the Run form of the dashboard
+        var statusClasses = {success: "is-success", failure: "is-danger", timeout: "is-warning"};
+        var form = document.getElementById("test-run");
+        form.addEventListener("submit", function(event) {
+            event.preventDefault();
+            var inputs = Array.prototype.map.call(form.querySelectorAll("input[name=arg]"), function(input) {
+                return input.value;
+            });
+            var args = inputs;
+            if (form.dataset.declared !== "true") {
+                args = inputs[0].split(/\s+/).filter(function(arg) { return arg !== ""; });
+            }
+            while (args.length > 0 && args[args.length - 1] === "") {
+                args.pop();
+            }
+
+            var button = form.querySelector("button");
+            button.classList.add("is-loading");
+            request("/run", {args: args}).catch(function(err) {
+                return {error: err.message};
+            }).then(function(result) {
+                var status = document.getElementById("test-status");
+                var link = document.getElementById("test-link");
+                status.className = "tag " + (statusClasses[result.status] || "is-danger");
+                status.textContent = result.status || "error";
+                document.getElementById("test-duration").textContent = result.duration || "";
+                document.getElementById("test-output").textContent = result.error ? result.error : result.result;
+                link.hidden = !result.execution_id;
+                if (result.execution_id) {
+                    link.href = "/executions/" + encodeURIComponent(result.execution_id);
+                }
+                document.getElementById("test-result").hidden = false;
+            }).finally(function() {
+                button.classList.remove("is-loading");
+            });
+        });
+    })();
+    </script>
+</body>
+</html>
+`)
+//line internal/api/dashboard/templates/editor.qtpl:402
+}
+
+//line internal/api/dashboard/templates/editor.qtpl:402
+func WriteEditor(qq422016 qtio422016.Writer, data viewmodels.EditorData) {
+//line internal/api/dashboard/templates/editor.qtpl:402
+	qw422016 := qt422016.AcquireWriter(qq422016)
+//line internal/api/dashboard/templates/editor.qtpl:402
+	StreamEditor(qw422016, data)
+//line internal/api/dashboard/templates/editor.qtpl:402
+	qt422016.ReleaseWriter(qw422016)
+//line internal/api/dashboard/templates/editor.qtpl:402
+}
+
+//line internal/api/dashboard/templates/editor.qtpl:402
+func Editor(data viewmodels.EditorData) string {
+//line internal/api/dashboard/templates/editor.qtpl:402
+	qb422016 := qt422016.AcquireByteBuffer()
+//line internal/api/dashboard/templates/editor.qtpl:402
+	WriteEditor(qb422016, data)
+//line internal/api/dashboard/templates/editor.qtpl:402
+	qs422016 := string(qb422016.B)
+//line internal/api/dashboard/templates/editor.qtpl:402
+	qt422016.ReleaseByteBuffer(qb422016)
+//line internal/api/dashboard/templates/editor.qtpl:402
+	return qs422016
+//line internal/api/dashboard/templates/editor.qtpl:402
+}
@@ -0,0 +1,479 @@
+// Code generated by qtc from "execution.qtpl". DO NOT EDIT.
+// See https://github.com/valyala/quicktemplate for details.
+
+//line internal/api/dashboard/templates/execution.qtpl:1
+package templates
+
+//line internal/api/dashboard/templates/execution.qtpl:1
+import (
+	"strings"
+
+	"goction/internal/api/dashboard/assets"
+	"goction/internal/viewmodels"
+)
+
+//line internal/api/dashboard/templates/execution.qtpl:8
+import (
+	qtio422016 "io"
+
+	qt422016 "github.com/valyala/quicktemplate"
+)
+
+//line internal/api/dashboard/templates/execution.qtpl:8
+var (
+	_ = qtio422016.Copy
+	_ = qt422016.AcquireByteBuffer
+)
+
+//line internal/api/dashboard/templates/execution.qtpl:8
+func StreamExecution(qw422016 *qt422016.Writer, data viewmodels.ExecutionData) {
+//line internal/api/dashboard/templates/execution.qtpl:8
+	qw422016.N().S(`
+<!DOCTYPE html>
+<html lang="en" class="has-background-black-bis">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Goction - Execution `)
+//line internal/api/dashboard/templates/execution.qtpl:14
+	qw422016.E().S(data.ExecutionID)
+//line internal/api/dashboard/templates/execution.qtpl:14
+	qw422016.N().S(`</title>
+    <link rel="stylesheet" href="`)
+//line internal/api/dashboard/templates/execution.qtpl:15
+	qw422016.E().S(assets.Path("dashboard.css"))
+//line internal/api/dashboard/templates/execution.qtpl:15
+	qw422016.N().S(`">
+    <link rel="icon" type="image/png" href="`)
+//line internal/api/dashboard/templates/execution.qtpl:16
+	qw422016.E().S(assets.Path("goction.png"))
+//line internal/api/dashboard/templates/execution.qtpl:16
+	qw422016.N().S(`">
+    <style>
+        .log-line {
+            white-space: pre-wrap;
+            word-break: break-all;
+        }
+        .stream-stderr {
+            color: #f14668;
+        }
+        .stream-log {
+            color: #48c78e;
+        }
+    </style>
+</head>
+<body class="has-background-black-bis has-text-light">
+    <nav class="navbar is-black" role="navigation" aria-label="main navigation">
+        <div class="navbar-brand">
+            <a class="navbar-item" href="/">
+                <img src="`)
+//line internal/api/dashboard/templates/execution.qtpl:34
+	qw422016.E().S(assets.Path("goction.png"))
+//line internal/api/dashboard/templates/execution.qtpl:34
+	qw422016.N().S(`" alt="Goction Logo" height="28">
+                <strong class="ml-2">Goction Dashboard</strong>
+            </a>
+            <div class="navbar-item">
+                <span class="tag is-primary">Version: `)
+//line internal/api/dashboard/templates/execution.qtpl:38
+	qw422016.E().S(data.GoctionVersion)
+//line internal/api/dashboard/templates/execution.qtpl:38
+	qw422016.N().S(`</span>
+            </div>
+        </div>
+    </nav>
+
+    <main>
+        <section class="section">
+            <div class="container">
+                <h1 class="title has-text-primary">Execution</h1>
+                <p class="subtitle has-text-grey-light">`)
+//line internal/api/dashboard/templates/execution.qtpl:47
+	qw422016.E().S(data.ExecutionID)
+//line internal/api/dashboard/templates/execution.qtpl:47
+	qw422016.N().S(`</p>
+
+                `)
+//line internal/api/dashboard/templates/execution.qtpl:49
+	if data.Found {
+//line internal/api/dashboard/templates/execution.qtpl:49
+		qw422016.N().S(`
+                `)
+//line internal/api/dashboard/templates/execution.qtpl:50
+		record := data.Record
+
+//line internal/api/dashboard/templates/execution.qtpl:50
+		qw422016.N().S(`
+                <div class="box has-background-black-ter">
+                    <table class="table is-fullwidth has-background-black-ter has-text-grey-light">
+                        <tbody>
+                            <tr>
+                                <th class="has-text-grey-light">Goction</th>
+                                <td><a class="has-text-primary" href="/goctions/`)
+//line internal/api/dashboard/templates/execution.qtpl:56
+		qw422016.N().U(record.Goction)
+//line internal/api/dashboard/templates/execution.qtpl:56
+		qw422016.N().S(`/history">`)
+//line internal/api/dashboard/templates/execution.qtpl:56
+		qw422016.E().S(record.Goction)
+//line internal/api/dashboard/templates/execution.qtpl:56
+		qw422016.N().S(`</a></td>
+                            </tr>
+                            <tr>
+                                <th class="has-text-grey-light">Status</th>
+                                <td><span class="tag `)
+//line internal/api/dashboard/templates/execution.qtpl:60
+		streamstatusClass(qw422016, record.Status)
+//line internal/api/dashboard/templates/execution.qtpl:60
+		qw422016.N().S(`">`)
+//line internal/api/dashboard/templates/execution.qtpl:60
+		qw422016.E().S(record.Status)
+//line internal/api/dashboard/templates/execution.qtpl:60
+		qw422016.N().S(`</span></td>
+                            </tr>
+                            <tr>
+                                <th class="has-text-grey-light">Arguments</th>
+                                <td class="is-family-monospace">
+                                    `)
+//line internal/api/dashboard/templates/execution.qtpl:65
+		if len(record.Args) == 0 {
+//line internal/api/dashboard/templates/execution.qtpl:65
+			qw422016.N().S(`
+                                        <span class="has-text-grey">none</span>
+                                    `)
+//line internal/api/dashboard/templates/execution.qtpl:67
+		} else {
+//line internal/api/dashboard/templates/execution.qtpl:67
+			qw422016.N().S(`
+                                        `)
+//line internal/api/dashboard/templates/execution.qtpl:68
+			qw422016.E().S(strings.Join(record.Args, " "))
+//line internal/api/dashboard/templates/execution.qtpl:68
+			qw422016.N().S(`
+                                    `)
+//line internal/api/dashboard/templates/execution.qtpl:69
+		}
+//line internal/api/dashboard/templates/execution.qtpl:69
+		qw422016.N().S(`
+                                </td>
+                            </tr>
+                            <tr>
+                                <th class="has-text-grey-light">Started</th>
+                                <td>`)
+//line internal/api/dashboard/templates/execution.qtpl:74
+		qw422016.E().S(record.Timestamp.Add(-record.Duration).Format("2006-01-02 15:04:05.000"))
+//line internal/api/dashboard/templates/execution.qtpl:74
+		qw422016.N().S(`</td>
+                            </tr>
+                            <tr>
+                                <th class="has-text-grey-light">Finished</th>
+                                <td>`)
+//line internal/api/dashboard/templates/execution.qtpl:78
+		qw422016.E().S(record.Timestamp.Format("2006-01-02 15:04:05.000"))
+//line internal/api/dashboard/templates/execution.qtpl:78
+		qw422016.N().S(`</td>
+                            </tr>
+                            <tr>
+                                <th class="has-text-grey-light">Duration</th>
+                                <td>`)
+//line internal/api/dashboard/templates/execution.qtpl:82
+		qw422016.E().S(record.Duration.String())
+//line internal/api/dashboard/templates/execution.qtpl:82
+		qw422016.N().S(`</td>
+                            </tr>
+                            <tr>
+                                <th class="has-text-grey-light">Caller</th>
+                                <td>`)
+//line internal/api/dashboard/templates/execution.qtpl:86
+		qw422016.E().S(record.Caller)
+//line internal/api/dashboard/templates/execution.qtpl:86
+		qw422016.N().S(` (`)
+//line internal/api/dashboard/templates/execution.qtpl:86
+		qw422016.E().S(record.Trigger)
+//line internal/api/dashboard/templates/execution.qtpl:86
+		qw422016.N().S(`)</td>
+                            </tr>
+                            <tr>
+                                <th class="has-text-grey-light">Host</th>
+                                <td>`)
+//line internal/api/dashboard/templates/execution.qtpl:90
+		qw422016.E().S(record.Host)
+//line internal/api/dashboard/templates/execution.qtpl:90
+		qw422016.N().S(`</td>
+                            </tr>
+                            <tr>
+                                <th class="has-text-grey-light">Version</th>
+                                <td>`)
+//line internal/api/dashboard/templates/execution.qtpl:94
+		qw422016.E().S(record.Version)
+//line internal/api/dashboard/templates/execution.qtpl:94
+		qw422016.N().S(`</td>
+                            </tr>
+                        </tbody>
+                    </table>
+
+                    <h2 class="subtitle has-text-primary">Result</h2>
+                    <pre class="has-background-black-bis has-text-grey-light">`)
+//line internal/api/dashboard/templates/execution.qtpl:100
+		qw422016.E().S(record.Result)
+//line internal/api/dashboard/templates/execution.qtpl:100
+		qw422016.N().S(`</pre>
+                    `)
+//line internal/api/dashboard/templates/execution.qtpl:101
+		if record.Error != "" {
+//line internal/api/dashboard/templates/execution.qtpl:101
+			qw422016.N().S(`
+                    <h2 class="subtitle has-text-danger mt-4">Error</h2>
+                    <pre class="has-background-black-bis has-text-danger">`)
+//line internal/api/dashboard/templates/execution.qtpl:103
+			qw422016.E().S(record.Error)
+//line internal/api/dashboard/templates/execution.qtpl:103
+			qw422016.N().S(`</pre>
+                    `)
+//line internal/api/dashboard/templates/execution.qtpl:104
+		}
+//line internal/api/dashboard/templates/execution.qtpl:104
+		qw422016.N().S(`
+
+                    <div class="mt-4">
+                        `)
+//line internal/api/dashboard/templates/execution.qtpl:107
+		if data.RerunBlocked != "" {
+//line internal/api/dashboard/templates/execution.qtpl:107
+			qw422016.N().S(`
+                            <button class="button is-primary" type="button" disabled title="`)
+//line internal/api/dashboard/templates/execution.qtpl:108
+			qw422016.E().S(data.RerunBlocked)
+//line internal/api/dashboard/templates/execution.qtpl:108
+			qw422016.N().S(`">Re-run with same arguments</button>
+                            <p class="help has-text-grey">`)
+//line internal/api/dashboard/templates/execution.qtpl:109
+			qw422016.E().S(data.RerunBlocked)
+//line internal/api/dashboard/templates/execution.qtpl:109
+			qw422016.N().S(`</p>
+                        `)
+//line internal/api/dashboard/templates/execution.qtpl:110
+		} else {
+//line internal/api/dashboard/templates/execution.qtpl:110
+			qw422016.N().S(`
+                            <button class="button is-primary" type="button" id="rerun" data-execution="`)
+//line internal/api/dashboard/templates/execution.qtpl:111
+			qw422016.E().S(data.ExecutionID)
+//line internal/api/dashboard/templates/execution.qtpl:111
+			qw422016.N().S(`">Re-run with same arguments</button>
+                            <p class="help has-text-danger" id="rerun-error"></p>
+                        `)
+//line internal/api/dashboard/templates/execution.qtpl:113
+		}
+//line internal/api/dashboard/templates/execution.qtpl:113
+		qw422016.N().S(`
+                    </div>
+                </div>
+                `)
+//line internal/api/dashboard/templates/execution.qtpl:116
+	} else {
+//line internal/api/dashboard/templates/execution.qtpl:116
+		qw422016.N().S(`
+                <div class="box has-background-black-ter">
+                    <p class="has-text-grey-light">No execution record was found with this ID.</p>
+                </div>
+                `)
+//line internal/api/dashboard/templates/execution.qtpl:120
+	}
+//line internal/api/dashboard/templates/execution.qtpl:120
+	qw422016.N().S(`
+
+                <h2 class="title is-4 has-text-primary mt-6" id="logs">Logs</h2>
+                <div class="box has-background-black-ter">
+                    `)
+//line internal/api/dashboard/templates/execution.qtpl:124
+	if data.LogsError != "" {
+//line internal/api/dashboard/templates/execution.qtpl:124
+		qw422016.N().S(`
+                        <p class="has-text-grey-light">`)
+//line internal/api/dashboard/templates/execution.qtpl:125
+		qw422016.E().S(data.LogsError)
+//line internal/api/dashboard/templates/execution.qtpl:125
+		qw422016.N().S(`</p>
+                    `)
+//line internal/api/dashboard/templates/execution.qtpl:126
+	} else if len(data.Lines) == 0 {
+//line internal/api/dashboard/templates/execution.qtpl:126
+		qw422016.N().S(`
+                        <p class="has-text-grey-light">This execution produced no output.</p>
+                    `)
+//line internal/api/dashboard/templates/execution.qtpl:128
+	} else {
+//line internal/api/dashboard/templates/execution.qtpl:128
+		qw422016.N().S(`
+                    <table class="table is-fullwidth is-narrow has-background-black-ter has-text-grey-light">
+                        <thead>
+                            <tr>
+                                <th class="has-text-grey-light">Time</th>
+                                <th class="has-text-grey-light">Stream</th>
+                                <th class="has-text-grey-light">Output</th>
+                            </tr>
+                        </thead>
+                        <tbody>
+                            `)
+//line internal/api/dashboard/templates/execution.qtpl:138
+		for _, line := range data.Lines {
+//line internal/api/dashboard/templates/execution.qtpl:138
+			qw422016.N().S(`
+                            <tr>
+                                <td>`)
+//line internal/api/dashboard/templates/execution.qtpl:140
+			qw422016.E().S(line.Time.Format("15:04:05.000"))
+//line internal/api/dashboard/templates/execution.qtpl:140
+			qw422016.N().S(`</td>
+                                <td class="stream-`)
+//line internal/api/dashboard/templates/execution.qtpl:141
+			qw422016.E().S(line.Stream)
+//line internal/api/dashboard/templates/execution.qtpl:141
+			qw422016.N().S(`">`)
+//line internal/api/dashboard/templates/execution.qtpl:141
+			qw422016.E().S(line.Stream)
+//line internal/api/dashboard/templates/execution.qtpl:141
+			qw422016.N().S(`</td>
+                                <td class="log-line is-family-monospace">`)
+//line internal/api/dashboard/templates/execution.qtpl:142
+			qw422016.E().S(line.Text)
+//line internal/api/dashboard/templates/execution.qtpl:142
+			qw422016.N().S(`</td>
+                            </tr>
+                            `)
+//line internal/api/dashboard/templates/execution.qtpl:144
+		}
+//line internal/api/dashboard/templates/execution.qtpl:144
+		qw422016.N().S(`
+                        </tbody>
+                    </table>
+                    `)
+//line internal/api/dashboard/templates/execution.qtpl:147
+	}
+//line internal/api/dashboard/templates/execution.qtpl:147
+	qw422016.N().S(`
+                </div>
+                <a class="button is-dark" href="/">
+                    <span class="icon">`)
+//line internal/api/dashboard/templates/execution.qtpl:150
+	streamicon(qw422016, "arrow-left")
+//line internal/api/dashboard/templates/execution.qtpl:150
+	qw422016.N().S(`</span>
+                    <span>Back to the dashboard</span>
+                </a>
+            </div>
+        </section>
+    </main>
+
+    <script>
+    // Re-run: the new execution is opened once it has finished
+    (function() {
+        var button = document.getElementById("rerun");
+        if (!button) {
+            return;
+        }
+        var error = document.getElementById("rerun-error");
+        button.addEventListener("click", function() {
+            button.classList.add("is-loading");
+            error.textContent = "";
+            fetch("/executions/" + encodeURIComponent(button.dataset.execution) + "/rerun", {
+                method: "POST",
+                headers: {"Content-Type": "application/json"},
+                credentials: "same-origin",
+                body: "{}"
+            }).then(function(response) {
+                if (response.redirected) {
+                    throw new Error("Session expired, please log in again");
+                }
+                return response.json();
+            }).then(function(result) {
+                if (!result.execution_id) {
+                    throw new Error(result.error || "The goction could not be run");
+                }
+                window.location = "/executions/" + encodeURIComponent(result.execution_id);
+            }).catch(function(err) {
+                error.textContent = err.message;
+                button.classList.remove("is-loading");
+            });
+        });
+    })();
+    </script>
+</body>
+</html>
+`)
+//line internal/api/dashboard/templates/execution.qtpl:192
+}
+
+//line internal/api/dashboard/templates/execution.qtpl:192
+func WriteExecution(qq422016 qtio422016.Writer, data viewmodels.ExecutionData) {
+//line internal/api/dashboard/templates/execution.qtpl:192
+	qw422016 := qt422016.AcquireWriter(qq422016)
+//line internal/api/dashboard/templates/execution.qtpl:192
+	StreamExecution(qw422016, data)
+//line internal/api/dashboard/templates/execution.qtpl:192
+	qt422016.ReleaseWriter(qw422016)
+//line internal/api/dashboard/templates/execution.qtpl:192
+}
+
+//line internal/api/dashboard/templates/execution.qtpl:192
+func Execution(data viewmodels.ExecutionData) string {
+//line internal/api/dashboard/templates/execution.qtpl:192
+	qb422016 := qt422016.AcquireByteBuffer()
+//line internal/api/dashboard/templates/execution.qtpl:192
+	WriteExecution(qb422016, data)
+//line internal/api/dashboard/templates/execution.qtpl:192
+	qs422016 := string(qb422016.B)
+//line internal/api/dashboard/templates/execution.qtpl:192
+	qt422016.ReleaseByteBuffer(qb422016)
+//line internal/api/dashboard/templates/execution.qtpl:192
+	return qs422016
+//line internal/api/dashboard/templates/execution.qtpl:192
+}
+
+//line internal/api/dashboard/templates/execution.qtpl:194
+func streamstatusClass(qw422016 *qt422016.Writer, status string) {
+//line internal/api/dashboard/templates/execution.qtpl:194
+	switch status {
+//line internal/api/dashboard/templates/execution.qtpl:194
+	case "success":
+//line internal/api/dashboard/templates/execution.qtpl:194
+		qw422016.N().S(`is-success`)
+//line internal/api/dashboard/templates/execution.qtpl:194
+	case "timeout":
+//line internal/api/dashboard/templates/execution.qtpl:194
+		qw422016.N().S(`is-warning`)
+//line internal/api/dashboard/templates/execution.qtpl:194
+	default:
+//line internal/api/dashboard/templates/execution.qtpl:194
+		qw422016.N().S(`is-danger`)
+//line internal/api/dashboard/templates/execution.qtpl:194
+	}
+//line internal/api/dashboard/templates/execution.qtpl:194
+}
+
+//line internal/api/dashboard/templates/execution.qtpl:194
+func writestatusClass(qq422016 qtio422016.Writer, status string) {
+//line internal/api/dashboard/templates/execution.qtpl:194
+	qw422016 := qt422016.AcquireWriter(qq422016)
+//line internal/api/dashboard/templates/execution.qtpl:194
+	streamstatusClass(qw422016, status)
+//line internal/api/dashboard/templates/execution.qtpl:194
+	qt422016.ReleaseWriter(qw422016)
+//line internal/api/dashboard/templates/execution.qtpl:194
+}
+
+//line internal/api/dashboard/templates/execution.qtpl:194
+func statusClass(status string) string {
+//line internal/api/dashboard/templates/execution.qtpl:194
+	qb422016 := qt422016.AcquireByteBuffer()
+//line internal/api/dashboard/templates/execution.qtpl:194
+	writestatusClass(qb422016, status)
+//line internal/api/dashboard/templates/execution.qtpl:194
+	qs422016 := string(qb422016.B)
+//line internal/api/dashboard/templates/execution.qtpl:194
+	qt422016.ReleaseByteBuffer(qb422016)
+//line internal/api/dashboard/templates/execution.qtpl:194
+	return qs422016
+//line internal/api/dashboard/templates/execution.qtpl:194
+}
@@ -0,0 +1,374 @@
+// Code generated by qtc from "history.qtpl". DO NOT EDIT.
+// See https://github.com/valyala/quicktemplate for details.
+
+//line internal/api/dashboard/templates/history.qtpl:1
+package templates
+
+//line internal/api/dashboard/templates/history.qtpl:1
+import (
+	"goction/internal/api/dashboard/assets"
+	"goction/internal/viewmodels"
+)
+
+//line internal/api/dashboard/templates/history.qtpl:6
+import (
+	qtio422016 "io"
+
+	qt422016 "github.com/valyala/quicktemplate"
+)
+
+//line internal/api/dashboard/templates/history.qtpl:6
+var (
+	_ = qtio422016.Copy
+	_ = qt422016.AcquireByteBuffer
+)
+
+//line internal/api/dashboard/templates/history.qtpl:6
+func StreamHistory(qw422016 *qt422016.Writer, data viewmodels.HistoryData) {
+//line internal/api/dashboard/templates/history.qtpl:6
+	qw422016.N().S(`
+<!DOCTYPE html>
+<html lang="en" class="has-background-black-bis">
+<head>
+    <meta charset="UTF-8">
+    <meta name="viewport" content="width=device-width, initial-scale=1.0">
+    <title>Goction - `)
+//line internal/api/dashboard/templates/history.qtpl:12
+	qw422016.E().S(data.Goction)
+//line internal/api/dashboard/templates/history.qtpl:12
+	qw422016.N().S(` history</title>
+    <link rel="stylesheet" href="`)
+//line internal/api/dashboard/templates/history.qtpl:13
+	qw422016.E().S(assets.Path("dashboard.css"))
+//line internal/api/dashboard/templates/history.qtpl:13
+	qw422016.N().S(`">
+    <link rel="icon" type="image/png" href="`)
+//line internal/api/dashboard/templates/history.qtpl:14
+	qw422016.E().S(assets.Path("goction.png"))
+//line internal/api/dashboard/templates/history.qtpl:14
+	qw422016.N().S(`">
+</head>
+<body class="has-background-black-bis has-text-light">
+    <nav class="navbar is-black" role="navigation" aria-label="main navigation">
+        <div class="navbar-brand">
+            <a class="navbar-item" href="/">
+                <img src="`)
+//line internal/api/dashboard/templates/history.qtpl:20
+	qw422016.E().S(assets.Path("goction.png"))
+//line internal/api/dashboard/templates/history.qtpl:20
+	qw422016.N().S(`" alt="Goction Logo" height="28">
+                <strong class="ml-2">Goction Dashboard</strong>
+            </a>
+            <div class="navbar-item">
+                <span class="tag is-primary">Version: `)
+//line internal/api/dashboard/templates/history.qtpl:24
+	qw422016.E().S(data.GoctionVersion)
+//line internal/api/dashboard/templates/history.qtpl:24
+	qw422016.N().S(`</span>
+            </div>
+        </div>
+    </nav>
+
+    <main>
+        <section class="section">
+            <div class="container">
+                <h1 class="title has-text-primary">Execution History</h1>
+                <p class="subtitle has-text-grey-light">`)
+//line internal/api/dashboard/templates/history.qtpl:33
+	qw422016.E().S(data.Goction)
+//line internal/api/dashboard/templates/history.qtpl:33
+	qw422016.N().S(` &middot; `)
+//line internal/api/dashboard/templates/history.qtpl:33
+	qw422016.N().D(data.Total)
+//line internal/api/dashboard/templates/history.qtpl:33
+	qw422016.N().S(` executions</p>
+
+                <div class="tabs is-toggle is-small">
+                    <ul>
+                        `)
+//line internal/api/dashboard/templates/history.qtpl:37
+	streamstatusTab(qw422016, data, "", "All")
+//line internal/api/dashboard/templates/history.qtpl:37
+	qw422016.N().S(`
+                        `)
+//line internal/api/dashboard/templates/history.qtpl:38
+	streamstatusTab(qw422016, data, "success", "Success")
+//line internal/api/dashboard/templates/history.qtpl:38
+	qw422016.N().S(`
+                        `)
+//line internal/api/dashboard/templates/history.qtpl:39
+	streamstatusTab(qw422016, data, "failure", "Failure")
+//line internal/api/dashboard/templates/history.qtpl:39
+	qw422016.N().S(`
+                        `)
+//line internal/api/dashboard/templates/history.qtpl:40
+	streamstatusTab(qw422016, data, "timeout", "Timeout")
+//line internal/api/dashboard/templates/history.qtpl:40
+	qw422016.N().S(`
+                    </ul>
+                </div>
+
+                <div class="box has-background-black-ter">
+                    `)
+//line internal/api/dashboard/templates/history.qtpl:45
+	if data.Error != "" {
+//line internal/api/dashboard/templates/history.qtpl:45
+		qw422016.N().S(`
+                        <p class="has-text-danger">`)
+//line internal/api/dashboard/templates/history.qtpl:46
+		qw422016.E().S(data.Error)
+//line internal/api/dashboard/templates/history.qtpl:46
+		qw422016.N().S(`</p>
+                    `)
+//line internal/api/dashboard/templates/history.qtpl:47
+	} else if len(data.Records) == 0 {
+//line internal/api/dashboard/templates/history.qtpl:47
+		qw422016.N().S(`
+                        <p class="has-text-grey-light">No executions.</p>
+                    `)
+//line internal/api/dashboard/templates/history.qtpl:49
+	} else {
+//line internal/api/dashboard/templates/history.qtpl:49
+		qw422016.N().S(`
+                    <table class="table is-fullwidth has-background-black-ter has-text-grey-light">
+                        <thead>
+                            <tr>
+                                <th class="has-text-grey-light">Timestamp</th>
+                                <th class="has-text-grey-light">Status</th>
+                                <th class="has-text-grey-light">Duration</th>
+                                <th class="has-text-grey-light">Caller</th>
+                                <th class="has-text-grey-light">Result</th>
+                                <th class="has-text-grey-light"></th>
+                            </tr>
+                        </thead>
+                        <tbody>
+                            `)
+//line internal/api/dashboard/templates/history.qtpl:62
+		for _, record := range data.Records {
+//line internal/api/dashboard/templates/history.qtpl:62
+			qw422016.N().S(`
+                            <tr>
+                                <td>`)
+//line internal/api/dashboard/templates/history.qtpl:64
+			qw422016.E().S(record.Timestamp.Format("2006-01-02 15:04:05"))
+//line internal/api/dashboard/templates/history.qtpl:64
+			qw422016.N().S(`</td>
+                                <td><span class="tag `)
+//line internal/api/dashboard/templates/history.qtpl:65
+			streamstatusClass(qw422016, record.Status)
+//line internal/api/dashboard/templates/history.qtpl:65
+			qw422016.N().S(`">`)
+//line internal/api/dashboard/templates/history.qtpl:65
+			qw422016.E().S(record.Status)
+//line internal/api/dashboard/templates/history.qtpl:65
+			qw422016.N().S(`</span></td>
+                                <td>`)
+//line internal/api/dashboard/templates/history.qtpl:66
+			qw422016.E().S(record.Duration.String())
+//line internal/api/dashboard/templates/history.qtpl:66
+			qw422016.N().S(`</td>
+                                <td>`)
+//line internal/api/dashboard/templates/history.qtpl:67
+			qw422016.E().S(record.Caller)
+//line internal/api/dashboard/templates/history.qtpl:67
+			qw422016.N().S(`</td>
+                                <td>
+                                    `)
+//line internal/api/dashboard/templates/history.qtpl:69
+			if record.Error != "" {
+//line internal/api/dashboard/templates/history.qtpl:69
+				qw422016.N().S(`
+                                        <span class="has-text-danger">`)
+//line internal/api/dashboard/templates/history.qtpl:70
+				qw422016.E().S(truncate(record.Error, 80))
+//line internal/api/dashboard/templates/history.qtpl:70
+				qw422016.N().S(`</span>
+                                    `)
+//line internal/api/dashboard/templates/history.qtpl:71
+			} else {
+//line internal/api/dashboard/templates/history.qtpl:71
+				qw422016.N().S(`
+                                        `)
+//line internal/api/dashboard/templates/history.qtpl:72
+				qw422016.E().S(truncate(record.Result, 80))
+//line internal/api/dashboard/templates/history.qtpl:72
+				qw422016.N().S(`
+                                    `)
+//line internal/api/dashboard/templates/history.qtpl:73
+			}
+//line internal/api/dashboard/templates/history.qtpl:73
+			qw422016.N().S(`
+                                </td>
+                                <td>
+                                    `)
+//line internal/api/dashboard/templates/history.qtpl:76
+			if record.ID != "" {
+//line internal/api/dashboard/templates/history.qtpl:76
+				qw422016.N().S(`
+                                        <a class="has-text-primary" href="/executions/`)
+//line internal/api/dashboard/templates/history.qtpl:77
+				qw422016.N().U(record.ID)
+//line internal/api/dashboard/templates/history.qtpl:77
+				qw422016.N().S(`">Details</a>
+                                    `)
+//line internal/api/dashboard/templates/history.qtpl:78
+			}
+//line internal/api/dashboard/templates/history.qtpl:78
+			qw422016.N().S(`
+                                </td>
+                            </tr>
+                            `)
+//line internal/api/dashboard/templates/history.qtpl:81
+		}
+//line internal/api/dashboard/templates/history.qtpl:81
+		qw422016.N().S(`
+                        </tbody>
+                    </table>
+                    `)
+//line internal/api/dashboard/templates/history.qtpl:84
+	}
+//line internal/api/dashboard/templates/history.qtpl:84
+	qw422016.N().S(`
+                </div>
+
+                <nav class="pagination is-small" role="navigation" aria-label="pagination">
+                    `)
+//line internal/api/dashboard/templates/history.qtpl:88
+	if data.Cursor != "" {
+//line internal/api/dashboard/templates/history.qtpl:88
+		qw422016.N().S(`
+                        <a class="pagination-previous" href="?status=`)
+//line internal/api/dashboard/templates/history.qtpl:89
+		qw422016.N().U(data.Status)
+//line internal/api/dashboard/templates/history.qtpl:89
+		qw422016.N().S(`">Newest</a>
+                    `)
+//line internal/api/dashboard/templates/history.qtpl:90
+	}
+//line internal/api/dashboard/templates/history.qtpl:90
+	qw422016.N().S(`
+                    `)
+//line internal/api/dashboard/templates/history.qtpl:91
+	if data.NextCursor != "" {
+//line internal/api/dashboard/templates/history.qtpl:91
+		qw422016.N().S(`
+                        <a class="pagination-next" href="?status=`)
+//line internal/api/dashboard/templates/history.qtpl:92
+		qw422016.N().U(data.Status)
+//line internal/api/dashboard/templates/history.qtpl:92
+		qw422016.N().S(`&amp;cursor=`)
+//line internal/api/dashboard/templates/history.qtpl:92
+		qw422016.N().U(data.NextCursor)
+//line internal/api/dashboard/templates/history.qtpl:92
+		qw422016.N().S(`">Older</a>
+                    `)
+//line internal/api/dashboard/templates/history.qtpl:93
+	}
+//line internal/api/dashboard/templates/history.qtpl:93
+	qw422016.N().S(`
+                </nav>
+
+                <a class="button is-dark mt-4" href="/">
+                    <span class="icon">`)
+//line internal/api/dashboard/templates/history.qtpl:97
+	streamicon(qw422016, "arrow-left")
+//line internal/api/dashboard/templates/history.qtpl:97
+	qw422016.N().S(`</span>
+                    <span>Back to the dashboard</span>
+                </a>
+            </div>
+        </section>
+    </main>
+</body>
+</html>
+`)
+//line internal/api/dashboard/templates/history.qtpl:105
+}
+
+//line internal/api/dashboard/templates/history.qtpl:105
+func WriteHistory(qq422016 qtio422016.Writer, data viewmodels.HistoryData) {
+//line internal/api/dashboard/templates/history.qtpl:105
+	qw422016 := qt422016.AcquireWriter(qq422016)
+//line internal/api/dashboard/templates/history.qtpl:105
+	StreamHistory(qw422016, data)
+//line internal/api/dashboard/templates/history.qtpl:105
+	qt422016.ReleaseWriter(qw422016)
+//line internal/api/dashboard/templates/history.qtpl:105
+}
+
+//line internal/api/dashboard/templates/history.qtpl:105
+func History(data viewmodels.HistoryData) string {
+//line internal/api/dashboard/templates/history.qtpl:105
+	qb422016 := qt422016.AcquireByteBuffer()
+//line internal/api/dashboard/templates/history.qtpl:105
+	WriteHistory(qb422016, data)
+//line internal/api/dashboard/templates/history.qtpl:105
+	qs422016 := string(qb422016.B)
+//line internal/api/dashboard/templates/history.qtpl:105
+	qt422016.ReleaseByteBuffer(qb422016)
+//line internal/api/dashboard/templates/history.qtpl:105
+	return qs422016
+//line internal/api/dashboard/templates/history.qtpl:105
+}
+
+//line internal/api/dashboard/templates/history.qtpl:107
+func streamstatusTab(qw422016 *qt422016.Writer, data viewmodels.HistoryData, status, label string) {
+//line internal/api/dashboard/templates/history.qtpl:107
+	qw422016.N().S(`
+<li`)
+//line internal/api/dashboard/templates/history.qtpl:108
+	if data.Status == status {
+//line internal/api/dashboard/templates/history.qtpl:108
+		qw422016.N().S(` class="is-active"`)
+//line internal/api/dashboard/templates/history.qtpl:108
+	}
+//line internal/api/dashboard/templates/history.qtpl:108
+	qw422016.N().S(`><a href="?status=`)
+//line internal/api/dashboard/templates/history.qtpl:108
+	qw422016.N().U(status)
+//line internal/api/dashboard/templates/history.qtpl:108
+	qw422016.N().S(`">`)
+//line internal/api/dashboard/templates/history.qtpl:108
+	qw422016.E().S(label)
+//line internal/api/dashboard/templates/history.qtpl:108
+	qw422016.N().S(`</a></li>
+`)
+//line internal/api/dashboard/templates/history.qtpl:109
+}
+
+//line internal/api/dashboard/templates/history.qtpl:109
+func writestatusTab(qq422016 qtio422016.Writer, data viewmodels.HistoryData, status, label string) {
+//line internal/api/dashboard/templates/history.qtpl:109
+	qw422016 := qt422016.AcquireWriter(qq422016)
+//line internal/api/dashboard/templates/history.qtpl:109
+	streamstatusTab(qw422016, data, status, label)
+//line internal/api/dashboard/templates/history.qtpl:109
+	qt422016.ReleaseWriter(qw422016)
+//line internal/api/dashboard/templates/history.qtpl:109
+}
+
+//line internal/api/dashboard/templates/history.qtpl:109
+func statusTab(data viewmodels.HistoryData, status, label string) string {
+//line internal/api/dashboard/templates/history.qtpl:109
+	qb422016 := qt422016.AcquireByteBuffer()
+//line internal/api/dashboard/templates/history.qtpl:109
+	writestatusTab(qb422016, data, status, label)
+//line internal/api/dashboard/templates/history.qtpl:109
+	qs422016 := string(qb422016.B)
+//line internal/api/dashboard/templates/history.qtpl:109
+	qt422016.ReleaseByteBuffer(qb422016)
+//line internal/api/dashboard/templates/history.qtpl:109
+	return qs422016
+//line internal/api/dashboard/templates/history.qtpl:109
+}
+
+// truncate shortens s to at most n runes for table cells
+//
+//line internal/api/dashboard/templates/history.qtpl:112
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n]) + "…"
+}
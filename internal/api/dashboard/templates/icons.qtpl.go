@@ -0,0 +1,94 @@
+// Code generated by qtc from "icons.qtpl". DO NOT EDIT.
+// See https://github.com/valyala/quicktemplate for details.
+
+// Icons drawn inline as SVG, so that the dashboard needs no icon font.
+//
+
+//line internal/api/dashboard/templates/icons.qtpl:3
+package templates
+
+//line internal/api/dashboard/templates/icons.qtpl:3
+import (
+	qtio422016 "io"
+
+	qt422016 "github.com/valyala/quicktemplate"
+)
+
+//line internal/api/dashboard/templates/icons.qtpl:3
+var (
+	_ = qtio422016.Copy
+	_ = qt422016.AcquireByteBuffer
+)
+
+//line internal/api/dashboard/templates/icons.qtpl:3
+func streamicon(qw422016 *qt422016.Writer, name string) {
+//line internal/api/dashboard/templates/icons.qtpl:3
+	qw422016.N().S(`
+<svg viewBox="0 0 24 24" fill="none" stroke="currentColor" stroke-width="2" stroke-linecap="round" stroke-linejoin="round" aria-hidden="true">
+`)
+//line internal/api/dashboard/templates/icons.qtpl:5
+	switch name {
+//line internal/api/dashboard/templates/icons.qtpl:6
+	case "book":
+//line internal/api/dashboard/templates/icons.qtpl:6
+		qw422016.N().S(`
+    <path d="M4 19.5A2.5 2.5 0 0 1 6.5 17H20"/><path d="M6.5 2H20v20H6.5A2.5 2.5 0 0 1 4 19.5v-15A2.5 2.5 0 0 1 6.5 2z"/>
+`)
+//line internal/api/dashboard/templates/icons.qtpl:8
+	case "sign-out":
+//line internal/api/dashboard/templates/icons.qtpl:8
+		qw422016.N().S(`
+    <path d="M9 21H5a2 2 0 0 1-2-2V5a2 2 0 0 1 2-2h4"/><polyline points="16 17 21 12 16 7"/><line x1="21" y1="12" x2="9" y2="12"/>
+`)
+//line internal/api/dashboard/templates/icons.qtpl:10
+	case "arrow-left":
+//line internal/api/dashboard/templates/icons.qtpl:10
+		qw422016.N().S(`
+    <line x1="19" y1="12" x2="5" y2="12"/><polyline points="12 19 5 12 12 5"/>
+`)
+//line internal/api/dashboard/templates/icons.qtpl:12
+	case "user":
+//line internal/api/dashboard/templates/icons.qtpl:12
+		qw422016.N().S(`
+    <path d="M20 21v-2a4 4 0 0 0-4-4H8a4 4 0 0 0-4 4v2"/><circle cx="12" cy="7" r="4"/>
+`)
+//line internal/api/dashboard/templates/icons.qtpl:14
+	case "lock":
+//line internal/api/dashboard/templates/icons.qtpl:14
+		qw422016.N().S(`
+    <rect x="3" y="11" width="18" height="11" rx="2" ry="2"/><path d="M7 11V7a5 5 0 0 1 10 0v4"/>
+`)
+//line internal/api/dashboard/templates/icons.qtpl:16
+	}
+//line internal/api/dashboard/templates/icons.qtpl:16
+	qw422016.N().S(`
+</svg>
+`)
+//line internal/api/dashboard/templates/icons.qtpl:18
+}
+
+//line internal/api/dashboard/templates/icons.qtpl:18
+func writeicon(qq422016 qtio422016.Writer, name string) {
+//line internal/api/dashboard/templates/icons.qtpl:18
+	qw422016 := qt422016.AcquireWriter(qq422016)
+//line internal/api/dashboard/templates/icons.qtpl:18
+	streamicon(qw422016, name)
+//line internal/api/dashboard/templates/icons.qtpl:18
+	qt422016.ReleaseWriter(qw422016)
+//line internal/api/dashboard/templates/icons.qtpl:18
+}
+
+//line internal/api/dashboard/templates/icons.qtpl:18
+func icon(name string) string {
+//line internal/api/dashboard/templates/icons.qtpl:18
+	qb422016 := qt422016.AcquireByteBuffer()
+//line internal/api/dashboard/templates/icons.qtpl:18
+	writeicon(qb422016, name)
+//line internal/api/dashboard/templates/icons.qtpl:18
+	qs422016 := string(qb422016.B)
+//line internal/api/dashboard/templates/icons.qtpl:18
+	qt422016.ReleaseByteBuffer(qb422016)
+//line internal/api/dashboard/templates/icons.qtpl:18
+	return qs422016
+//line internal/api/dashboard/templates/icons.qtpl:18
+}
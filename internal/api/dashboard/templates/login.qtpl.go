@@ -5,21 +5,24 @@
 package templates
 
 //line internal/api/dashboard/templates/login.qtpl:1
+import "goction/internal/api/dashboard/assets"
+
+//line internal/api/dashboard/templates/login.qtpl:3
 import (
 	qtio422016 "io"
 
 	qt422016 "github.com/valyala/quicktemplate"
 )
 
-//line internal/api/dashboard/templates/login.qtpl:1
+//line internal/api/dashboard/templates/login.qtpl:3
 var (
 	_ = qtio422016.Copy
 	_ = qt422016.AcquireByteBuffer
 )
 
-//line internal/api/dashboard/templates/login.qtpl:1
+//line internal/api/dashboard/templates/login.qtpl:3
 func StreamLogin(qw422016 *qt422016.Writer, goctionVersion string) {
-//line internal/api/dashboard/templates/login.qtpl:1
+//line internal/api/dashboard/templates/login.qtpl:3
 	qw422016.N().S(`
 <!DOCTYPE html>
 <html lang="en" class="has-background-black-bis">
@@ -27,8 +30,16 @@ func StreamLogin(qw422016 *qt422016.Writer, goctionVersion string) {
     <meta charset="UTF-8">
     <meta name="viewport" content="width=device-width, initial-scale=1.0">
     <title>Goction - Login</title>
-    <link rel="stylesheet" href="https://cdn.jsdelivr.net/npm/bulma@0.9.3/css/bulma.min.css">
-    <script defer src="https://use.fontawesome.com/releases/v5.15.4/js/all.js"></script>
+    <link rel="stylesheet" href="`)
+//line internal/api/dashboard/templates/login.qtpl:10
+	qw422016.E().S(assets.Path("dashboard.css"))
+//line internal/api/dashboard/templates/login.qtpl:10
+	qw422016.N().S(`">
+    <link rel="icon" type="image/png" href="`)
+//line internal/api/dashboard/templates/login.qtpl:11
+	qw422016.E().S(assets.Path("goction.png"))
+//line internal/api/dashboard/templates/login.qtpl:11
+	qw422016.N().S(`">
     <style>
         body, html {
             height: 100%;
@@ -57,7 +68,11 @@ func StreamLogin(qw422016 *qt422016.Writer, goctionVersion string) {
                     <div class="column is-5-tablet is-4-desktop is-3-widescreen">
                         <div class="box has-background-black-ter">
                             <figure class="image is-96x96 mb-5" style="margin: 0 auto;">
-                                <img src="https://goction.github.io/images/goction.png" alt="Goction Logo">
+                                <img src="`)
+//line internal/api/dashboard/templates/login.qtpl:40
+	qw422016.E().S(assets.Path("goction.png"))
+//line internal/api/dashboard/templates/login.qtpl:40
+	qw422016.N().S(`" alt="Goction Logo">
                             </figure>
                             <h1 class="title has-text-centered has-text-light">Goction Dashboard</h1>
                             <form method="POST" action="/login">
@@ -66,7 +81,11 @@ func StreamLogin(qw422016 *qt422016.Writer, goctionVersion string) {
                                     <div class="control has-icons-left">
                                         <input class="input" type="text" name="username" required placeholder="Enter your username">
                                         <span class="icon is-small is-left">
-                                            <i class="fas fa-user"></i>
+                                            `)
+//line internal/api/dashboard/templates/login.qtpl:49
+	streamicon(qw422016, "user")
+//line internal/api/dashboard/templates/login.qtpl:49
+	qw422016.N().S(`
                                         </span>
                                     </div>
                                 </div>
@@ -75,7 +94,11 @@ func StreamLogin(qw422016 *qt422016.Writer, goctionVersion string) {
                                     <div class="control has-icons-left">
                                         <input class="input" type="password" name="password" required placeholder="Enter your password">
                                         <span class="icon is-small is-left">
-                                            <i class="fas fa-lock"></i>
+                                            `)
+//line internal/api/dashboard/templates/login.qtpl:58
+	streamicon(qw422016, "lock")
+//line internal/api/dashboard/templates/login.qtpl:58
+	qw422016.N().S(`
                                         </span>
                                     </div>
                                 </div>
@@ -87,9 +110,9 @@ func StreamLogin(qw422016 *qt422016.Writer, goctionVersion string) {
                             </form>
                             <div class="has-text-centered mt-4">
                                 <p class="has-text-light">Goction version: `)
-//line internal/api/dashboard/templates/login.qtpl:67
+//line internal/api/dashboard/templates/login.qtpl:69
 	qw422016.E().S(goctionVersion)
-//line internal/api/dashboard/templates/login.qtpl:67
+//line internal/api/dashboard/templates/login.qtpl:69
 	qw422016.N().S(`</p>
                                 <a href="https://goction.github.io" target="_blank" class="has-text-primary">Documentation</a>
                             </div>
@@ -102,31 +125,31 @@ func StreamLogin(qw422016 *qt422016.Writer, goctionVersion string) {
 </body>
 </html>
 `)
-//line internal/api/dashboard/templates/login.qtpl:78
+//line internal/api/dashboard/templates/login.qtpl:80
 }
 
-//line internal/api/dashboard/templates/login.qtpl:78
+//line internal/api/dashboard/templates/login.qtpl:80
 func WriteLogin(qq422016 qtio422016.Writer, goctionVersion string) {
-//line internal/api/dashboard/templates/login.qtpl:78
+//line internal/api/dashboard/templates/login.qtpl:80
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line internal/api/dashboard/templates/login.qtpl:78
+//line internal/api/dashboard/templates/login.qtpl:80
 	StreamLogin(qw422016, goctionVersion)
-//line internal/api/dashboard/templates/login.qtpl:78
+//line internal/api/dashboard/templates/login.qtpl:80
 	qt422016.ReleaseWriter(qw422016)
-//line internal/api/dashboard/templates/login.qtpl:78
+//line internal/api/dashboard/templates/login.qtpl:80
 }
 
-//line internal/api/dashboard/templates/login.qtpl:78
+//line internal/api/dashboard/templates/login.qtpl:80
 func Login(goctionVersion string) string {
-//line internal/api/dashboard/templates/login.qtpl:78
+//line internal/api/dashboard/templates/login.qtpl:80
 	qb422016 := qt422016.AcquireByteBuffer()
-//line internal/api/dashboard/templates/login.qtpl:78
+//line internal/api/dashboard/templates/login.qtpl:80
 	WriteLogin(qb422016, goctionVersion)
-//line internal/api/dashboard/templates/login.qtpl:78
+//line internal/api/dashboard/templates/login.qtpl:80
 	qs422016 := string(qb422016.B)
-//line internal/api/dashboard/templates/login.qtpl:78
+//line internal/api/dashboard/templates/login.qtpl:80
 	qt422016.ReleaseByteBuffer(qb422016)
-//line internal/api/dashboard/templates/login.qtpl:78
+//line internal/api/dashboard/templates/login.qtpl:80
 	return qs422016
-//line internal/api/dashboard/templates/login.qtpl:78
+//line internal/api/dashboard/templates/login.qtpl:80
 }
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"goction/internal/goctions"
+	"goction/internal/stats"
+)
+
+// systemSampleInterval is how often the system metrics shown by the dashboard are sampled
+const systemSampleInterval = 5 * time.Second
+
+// eventKeepAlive is how often an SSE comment is sent so that proxies keep an idle event stream open
+const eventKeepAlive = 15 * time.Second
+
+// liveEvent is a server-sent event of the live dashboard
+type liveEvent struct {
+	name string
+	data interface{}
+}
+
+// eventHub hands the executions of the server to the dashboards connected to /events.
+// It implements runner.Observer.
+type eventHub struct {
+	mu          sync.Mutex
+	subscribers map[chan liveEvent]struct{}
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{subscribers: make(map[chan liveEvent]struct{})}
+}
+
+// subscribe returns a channel receiving the events and the function to call to stop receiving them
+func (h *eventHub) subscribe() (<-chan liveEvent, func()) {
+	ch := make(chan liveEvent, 32)
+	h.mu.Lock()
+	h.subscribers[ch] = struct{}{}
+	h.mu.Unlock()
+	return ch, func() {
+		h.mu.Lock()
+		delete(h.subscribers, ch)
+		h.mu.Unlock()
+	}
+}
+
+// publish sends an event to every subscriber, dropping it for those whose buffer is full
+func (h *eventHub) publish(event liveEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// ExecutionStarted implements runner.Observer
+func (h *eventHub) ExecutionStarted(name string) {}
+
+// ExecutionFinished implements runner.Observer
+func (h *eventHub) ExecutionFinished(record stats.ExecutionRecord) {
+	h.publish(liveEvent{name: "execution", data: record})
+}
+
+// PluginLoadFailed implements runner.Observer
+func (h *eventHub) PluginLoadFailed(name string, err error) {}
+
+// dashboardSnapshot is the first event of /events, holding everything the live parts of the dashboard show
+type dashboardSnapshot struct {
+	System           interface{}                    `json:"system"`
+	Stats            map[string]*stats.GoctionStats `json:"stats"`
+	RecentExecutions []stats.ExecutionRecord        `json:"recent_executions"`
+}
+
+// handleDashboardEvents streams the dashboard updates as server-sent events: a snapshot, then the system
+// metrics as they are sampled and the executions and updated statistics of goctions as they finish
+func (s *Server) handleDashboardEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	// Subscribe before taking the snapshot so that no execution falls between them
+	events, unsubscribe := s.events.subscribe()
+	defer unsubscribe()
+	samples, stopSamples := s.collector.Subscribe()
+	defer stopSamples()
+
+	snapshot := dashboardSnapshot{Stats: s.stats.GetAllStats()}
+	for name := range snapshot.Stats {
+		if _, err := goctions.Dir(s.cfg().GoctionsDir, name); err != nil {
+			delete(snapshot.Stats, name)
+		}
+	}
+	if sample, ok := s.collector.Latest(); ok {
+		snapshot.System = sample
+	}
+	recent, _ := s.stats.QueryHistory(stats.HistoryQuery{Limit: 10})
+	snapshot.RecentExecutions = recent.Records
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	send := func(name string, data interface{}) {
+		encoded, _ := json.Marshal(data)
+		fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, encoded)
+	}
+	send("snapshot", snapshot)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(eventKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event := <-events:
+			send(event.name, event.data)
+			if record, ok := event.data.(stats.ExecutionRecord); ok {
+				if goctionStats, ok := s.stats.GetAllStats()[record.Goction]; ok {
+					send("stats", map[string]interface{}{"goction": record.Goction, "stats": goctionStats})
+				}
+			}
+		case sample := <-samples:
+			send("system", sample)
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		}
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"goction/internal/execlog"
+	"goction/internal/stats"
+
+	"github.com/gorilla/mux"
+)
+
+func (s *Server) handleGetGoctionHistory(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	goctionName := vars["goction"]
+
+	if !s.goctionExists(goctionName) {
+		http.Error(w, fmt.Sprintf("Goction not found: %s", goctionName), http.StatusNotFound)
+		return
+	}
+
+	query, err := parseHistoryQuery(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid query: %v", err), http.StatusBadRequest)
+		return
+	}
+	query.Goction = goctionName
+
+	s.writeHistoryPage(w, query)
+}
+
+func (s *Server) handleListExecutions(w http.ResponseWriter, r *http.Request) {
+	query, err := parseHistoryQuery(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid query: %v", err), http.StatusBadRequest)
+		return
+	}
+	query.Goction = r.URL.Query().Get("goction")
+
+	s.writeHistoryPage(w, query)
+}
+
+// handleGetExecutionLogs returns what an execution printed and logged
+func (s *Server) handleGetExecutionLogs(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	lines, err := s.runner.Logs().Read(id)
+	if errors.Is(err, execlog.ErrNotFound) {
+		http.Error(w, fmt.Sprintf("No logs found for execution %s", id), http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, execlog.ErrInvalidID) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to read logs: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"execution_id": id, "lines": lines})
+}
+
+func (s *Server) writeHistoryPage(w http.ResponseWriter, query stats.HistoryQuery) {
+	page, err := s.stats.QueryHistory(query)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid query: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(page)
+}
+
+// handleExportStats streams the execution history or per-goction statistics as CSV, JSONL or columnar JSON
+func (s *Server) handleExportStats(w http.ResponseWriter, r *http.Request) {
+	query, err := parseHistoryQuery(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid query: %v", err), http.StatusBadRequest)
+		return
+	}
+	query.Goction = r.URL.Query().Get("goction")
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = stats.FormatCSV
+	}
+	kind := r.URL.Query().Get("kind")
+	if kind == "" {
+		kind = stats.KindHistory
+	}
+	if err := stats.ValidateExport(format, kind); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid export: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	filename := fmt.Sprintf("goction-%s-%s.%s", kind, time.Now().Format("20060102-150405"), stats.FileExtension(format))
+	w.Header().Set("Content-Type", stats.ContentType(format))
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	if err := s.stats.Export(w, format, kind, query); err != nil {
+		// Headers and part of the body may already be sent, so the error can only be logged
+		s.log(r).WithError(err).Error("Failed to export stats")
+	}
+}
+
+// parseHistoryQuery reads the since, until, status, q, limit, cursor and order query parameters
+func parseHistoryQuery(r *http.Request) (stats.HistoryQuery, error) {
+	params := r.URL.Query()
+	now := time.Now()
+
+	var query stats.HistoryQuery
+	var err error
+
+	if query.Since, err = stats.ParseTime(params.Get("since"), now); err != nil {
+		return query, fmt.Errorf("since: %w", err)
+	}
+	if query.Until, err = stats.ParseTime(params.Get("until"), now); err != nil {
+		return query, fmt.Errorf("until: %w", err)
+	}
+	query.Statuses = stats.ParseStatuses(params.Get("status"))
+	query.Search = params.Get("q")
+	query.Cursor = params.Get("cursor")
+	query.Order = params.Get("order")
+
+	if limit := params.Get("limit"); limit != "" {
+		if query.Limit, err = strconv.Atoi(limit); err != nil {
+			return query, fmt.Errorf("limit must be an integer")
+		}
+	}
+
+	return query, query.Validate()
+}
+
+// goctionExists reports whether a goction is installed or has recorded statistics
+func (s *Server) goctionExists(name string) bool {
+	if _, ok := s.stats.GetStats(name); ok {
+		return true
+	}
+	info, err := os.Stat(filepath.Join(s.cfg().GoctionsDir, name))
+	return err == nil && info.IsDir()
+}
+
+// handleStatsTimeseries returns the execution volume, outcomes, latency percentiles and time-of-day heatmap
+// of a period. It backs the dashboard charts, so it is also served to dashboard sessions.
+func (s *Server) handleStatsTimeseries(w http.ResponseWriter, r *http.Request) {
+	query := stats.TimeseriesQuery{
+		Goction: r.URL.Query().Get("goction"),
+		Range:   r.URL.Query().Get("range"),
+	}
+	if offset := r.URL.Query().Get("utc_offset"); offset != "" {
+		minutes, err := strconv.Atoi(offset)
+		if err != nil {
+			http.Error(w, "Invalid query: utc_offset must be a number of minutes", http.StatusBadRequest)
+			return
+		}
+		query.UTCOffset = time.Duration(minutes) * time.Minute
+	}
+
+	series, err := s.stats.Timeseries(query)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid query: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(series)
+}
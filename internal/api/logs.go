@@ -0,0 +1,60 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"goction/internal/logging"
+)
+
+// defaultLogLines is the number of past entries returned by the logs endpoint when lines is not set
+const defaultLogLines = 100
+
+// handleGetLogs returns the last entries of the goction log as JSON lines, then keeps streaming new entries
+// when follow is set, until the client disconnects
+func (s *Server) handleGetLogs(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter, err := logging.NewFilter(query.Get("goction"), query.Get("level"), query.Get("since"), query.Get("grep"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid query: %v", err), http.StatusBadRequest)
+		return
+	}
+	lines := defaultLogLines
+	if value := query.Get("lines"); value != "" {
+		if lines, err = strconv.Atoi(value); err != nil || lines < 0 {
+			http.Error(w, "Invalid query: lines must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+	}
+	follow, _ := strconv.ParseBool(query.Get("follow"))
+
+	path := s.cfg().LogFile
+	entries, err := logging.Recent(path, filter, lines)
+	if err != nil && !follow {
+		http.Error(w, fmt.Sprintf("Failed to read logs: %v", err), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	encoder := json.NewEncoder(w)
+	for _, entry := range entries {
+		encoder.Encode(entry)
+	}
+	if !follow {
+		return
+	}
+
+	flusher, _ := w.(http.Flusher)
+	flush := func() {
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	flush()
+	logging.FollowEntries(r.Context(), path, filter, func(entry logging.Entry) {
+		encoder.Encode(entry)
+		flush()
+	})
+}
@@ -0,0 +1,264 @@
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"goction/internal/goctions"
+	"goction/internal/logging"
+
+	"github.com/gorilla/mux"
+	"github.com/sirupsen/logrus"
+)
+
+// maxUploadSize is the largest source archive or file accepted by the management endpoints
+const maxUploadSize = 64 << 20
+
+// goctionSources is the response of the management endpoints changing the files of a goction
+type goctionSources struct {
+	Name  string   `json:"name"`
+	Files []string `json:"files"`
+}
+
+// buildEvent is one JSON line of a streamed build: a line of compiler output, then the result or an error
+type buildEvent struct {
+	Line  string                `json:"line,omitempty"`
+	Build *goctions.BuildResult `json:"build,omitempty"`
+	Error string                `json:"error,omitempty"`
+}
+
+// handleCreateGoction creates a goction from the template
+func (s *Server) handleCreateGoction(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := goctions.Create(r.Context(), s.cfg().GoctionsDir, body.Name, body.Description); err != nil {
+		writeManageError(w, err)
+		return
+	}
+	s.logManagement(r, body.Name).Info("Goction created")
+
+	s.writeSources(w, body.Name, http.StatusCreated)
+}
+
+// handleUploadArchive installs a goction from a zip archive, or replaces the source files of an installed goction
+// with those at the top of the archive
+func (s *Server) handleUploadArchive(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["goction"]
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxUploadSize))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to read archive: %v", err), http.StatusBadRequest)
+		return
+	}
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid archive: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	cfg := s.cfg()
+	if _, err := goctions.Dir(cfg.GoctionsDir, name); errors.Is(err, goctions.ErrNotFound) {
+		if err := goctions.Import(cfg.GoctionsDir, name, archive); err != nil {
+			writeManageError(w, err)
+			return
+		}
+		s.logManagement(r, name).Info("Goction imported")
+		s.writeSources(w, name, http.StatusCreated)
+		return
+	}
+
+	files, err := goctions.ArchiveSources(archive)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(files) == 0 {
+		http.Error(w, "The archive contains no source files", http.StatusBadRequest)
+		return
+	}
+	if err := goctions.WriteSources(cfg.GoctionsDir, name, files, true); err != nil {
+		writeManageError(w, err)
+		return
+	}
+	s.logManagement(r, name).Info("Goction sources uploaded")
+	s.writeSources(w, name, http.StatusOK)
+}
+
+// handleUploadFile writes one source file of a goction with the request body
+func (s *Server) handleUploadFile(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	name, file := vars["goction"], vars["file"]
+	if !goctions.IsSourceFile(file) {
+		http.Error(w, fmt.Sprintf("%q is not a source file of a goction", file), http.StatusBadRequest)
+		return
+	}
+	content, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxUploadSize))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to read file: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := goctions.WriteSources(s.cfg().GoctionsDir, name, []goctions.File{{Name: file, Content: string(content)}}, false); err != nil {
+		writeManageError(w, err)
+		return
+	}
+	s.logManagement(r, name).WithField("file", file).Info("Goction file uploaded")
+	s.writeSources(w, name, http.StatusOK)
+}
+
+// handleBuildGoction builds the plugin of a goction. The result is returned once the build is over, with status 422
+// if the goction does not compile, or streamed as JSON lines when stream is set.
+func (s *Server) handleBuildGoction(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["goction"]
+	entry := s.logManagement(r, name)
+	dir := s.cfg().GoctionsDir
+	if _, err := goctions.Dir(dir, name); err != nil {
+		writeManageError(w, err)
+		return
+	}
+
+	if stream, _ := strconv.ParseBool(r.URL.Query().Get("stream")); stream {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		output := newBuildLogWriter(w)
+		result, err := goctions.Build(r.Context(), dir, name, output)
+		output.close()
+		if err != nil {
+			entry.WithError(err).Error("Failed to build goction")
+			output.encoder.Encode(buildEvent{Error: err.Error()})
+			return
+		}
+		entry.WithField("success", result.Success).Info("Goction built")
+		output.encoder.Encode(buildEvent{Build: result})
+		return
+	}
+
+	result, err := goctions.Build(r.Context(), dir, name, nil)
+	if err != nil {
+		entry.WithError(err).Error("Failed to build goction")
+		writeManageError(w, err)
+		return
+	}
+	entry.WithField("success", result.Success).Info("Goction built")
+
+	w.Header().Set("Content-Type", "application/json")
+	if !result.Success {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}
+	json.NewEncoder(w).Encode(result)
+}
+
+// handleDeleteGoction removes a goction and its statistics
+func (s *Server) handleDeleteGoction(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["goction"]
+	if err := goctions.Remove(s.cfg().GoctionsDir, name); err != nil {
+		writeManageError(w, err)
+		return
+	}
+	if err := s.stats.Reset(name); err != nil {
+		http.Error(w, fmt.Sprintf("Goction removed but failed to delete its statistics: %v", err), http.StatusInternalServerError)
+		return
+	}
+	s.logManagement(r, name).Info("Goction deleted")
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleSetDisabled returns the handler disabling or enabling a goction
+func (s *Server) handleSetDisabled(disabled bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := mux.Vars(r)["goction"]
+		if err := goctions.SetDisabled(s.cfg().GoctionsDir, name, disabled); err != nil {
+			writeManageError(w, err)
+			return
+		}
+		s.logManagement(r, name).WithField("disabled", disabled).Info("Goction state changed")
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"name": name, "disabled": disabled})
+	}
+}
+
+// logManagement returns the logger of a management request about the goction name, with the API key that sent it
+func (s *Server) logManagement(r *http.Request, name string) *logrus.Entry {
+	caller, _ := r.Context().Value(callerKey).(string)
+	return s.log(r).WithFields(logrus.Fields{logging.FieldGoction: name, "caller": caller})
+}
+
+// writeSources writes the source files of a goction as a goctionSources response
+func (s *Server) writeSources(w http.ResponseWriter, name string, status int) {
+	files, err := goctions.ReadSources(s.cfg().GoctionsDir, name)
+	if err != nil {
+		writeManageError(w, err)
+		return
+	}
+	response := goctionSources{Name: name, Files: []string{}}
+	for _, file := range files {
+		response.Files = append(response.Files, file.Name)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
+
+// writeManageError writes the error of a management request with the status matching its cause
+func writeManageError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, goctions.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, goctions.ErrExists):
+		status = http.StatusConflict
+	case errors.Is(err, goctions.ErrInvalidName), errors.Is(err, goctions.ErrInvalidArchive):
+		status = http.StatusBadRequest
+	}
+	http.Error(w, err.Error(), status)
+}
+
+// buildLogWriter sends every line of compiler output it receives as a buildEvent
+type buildLogWriter struct {
+	encoder *json.Encoder
+	flusher http.Flusher
+	partial []byte
+}
+
+func newBuildLogWriter(w http.ResponseWriter) *buildLogWriter {
+	flusher, _ := w.(http.Flusher)
+	return &buildLogWriter{encoder: json.NewEncoder(w), flusher: flusher}
+}
+
+func (b *buildLogWriter) Write(p []byte) (int, error) {
+	b.partial = append(b.partial, p...)
+	for {
+		i := bytes.IndexByte(b.partial, '\n')
+		if i < 0 {
+			break
+		}
+		b.encoder.Encode(buildEvent{Line: string(b.partial[:i])})
+		b.partial = b.partial[i+1:]
+	}
+	if b.flusher != nil {
+		b.flusher.Flush()
+	}
+	return len(p), nil
+}
+
+// close sends the last line of output if it did not end with a newline
+func (b *buildLogWriter) close() {
+	if len(b.partial) > 0 {
+		b.encoder.Encode(buildEvent{Line: string(b.partial)})
+		b.partial = nil
+	}
+}
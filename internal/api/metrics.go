@@ -0,0 +1,176 @@
+package api
+
+import (
+	"bufio"
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"goction/internal/config"
+	"goction/internal/stats"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// serverMetrics groups the instruments exported on /metrics
+type serverMetrics struct {
+	registry *prometheus.Registry
+	handler  http.Handler
+
+	calls            *prometheus.CounterVec
+	successes        *prometheus.CounterVec
+	failures         *prometheus.CounterVec
+	timeouts         *prometheus.CounterVec
+	duration         *prometheus.HistogramVec
+	inFlight         *prometheus.GaugeVec
+	pluginLoadErrors *prometheus.CounterVec
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+}
+
+// durationBuckets are histogram buckets (in seconds) suited to goction and HTTP latencies
+var durationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+func newServerMetrics() (*serverMetrics, error) {
+	m := &serverMetrics{
+		registry: prometheus.NewRegistry(),
+		calls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "goction_calls_total", Help: "Total number of goction executions.",
+		}, []string{"goction"}),
+		successes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "goction_successes_total", Help: "Total number of successful goction executions.",
+		}, []string{"goction"}),
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "goction_failures_total", Help: "Total number of failed goction executions.",
+		}, []string{"goction"}),
+		timeouts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "goction_timeouts_total", Help: "Total number of goction processes killed for exceeding their timeout.",
+		}, []string{"goction"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "goction_execution_duration_seconds", Help: "Duration of goction executions in seconds.", Buckets: durationBuckets,
+		}, []string{"goction"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "goction_executions_in_flight", Help: "Number of goction executions currently running.",
+		}, []string{"goction"}),
+		pluginLoadErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "goction_plugin_load_errors_total", Help: "Total number of errors while loading goction plugins.",
+		}, []string{"goction"}),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "goction_http_requests_total", Help: "Total number of HTTP requests handled.",
+		}, []string{"method", "route", "code"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name: "goction_http_request_duration_seconds", Help: "Duration of HTTP requests in seconds.", Buckets: durationBuckets,
+		}, []string{"method", "route"}),
+	}
+
+	buildInfo := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name:        "goction_build_info",
+		Help:        "Build information, value is always 1.",
+		ConstLabels: prometheus.Labels{"version": config.GoctionVersion},
+	})
+	buildInfo.Set(1)
+
+	for _, c := range []prometheus.Collector{
+		m.calls, m.successes, m.failures, m.timeouts, m.duration, m.inFlight, m.pluginLoadErrors,
+		m.httpRequests, m.httpDuration, buildInfo,
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		collectors.NewGoCollector(),
+	} {
+		if err := m.registry.Register(c); err != nil {
+			return nil, fmt.Errorf("failed to register metrics: %w", err)
+		}
+	}
+	m.handler = promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{EnableOpenMetrics: true})
+
+	return m, nil
+}
+
+func (m *serverMetrics) observeHTTP(r *http.Request, code int, duration time.Duration) {
+	route := "unmatched"
+	if current := mux.CurrentRoute(r); current != nil {
+		if tpl, err := current.GetPathTemplate(); err == nil {
+			route = tpl
+		}
+	}
+	m.httpRequests.WithLabelValues(r.Method, route, strconv.Itoa(code)).Inc()
+	m.httpDuration.WithLabelValues(r.Method, route).Observe(duration.Seconds())
+}
+
+// ExecutionStarted implements runner.Observer
+func (m *serverMetrics) ExecutionStarted(name string) {
+	m.calls.WithLabelValues(name).Inc()
+	m.inFlight.WithLabelValues(name).Inc()
+}
+
+// ExecutionFinished implements runner.Observer
+func (m *serverMetrics) ExecutionFinished(record stats.ExecutionRecord) {
+	name := record.Goction
+	m.inFlight.WithLabelValues(name).Dec()
+	m.duration.WithLabelValues(name).Observe(record.Duration.Seconds())
+	switch record.Status {
+	case stats.StatusSuccess:
+		m.successes.WithLabelValues(name).Inc()
+	case stats.StatusTimeout:
+		m.timeouts.WithLabelValues(name).Inc()
+		m.failures.WithLabelValues(name).Inc()
+	default:
+		m.failures.WithLabelValues(name).Inc()
+	}
+}
+
+// PluginLoadFailed implements runner.Observer
+func (m *serverMetrics) PluginLoadFailed(name string, err error) {
+	m.pluginLoadErrors.WithLabelValues(name).Inc()
+}
+
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	s.metrics.handler.ServeHTTP(w, r)
+}
+
+// metricsAuthMiddleware protects /metrics with the metrics token when one is configured
+func (s *Server) metricsAuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if metricsToken := s.cfg().MetricsToken; metricsToken != "" {
+			token := strings.TrimSpace(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+			if subtle.ConstantTimeCompare([]byte(token), []byte(metricsToken)) != 1 {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="goction metrics"`)
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	}
+}
+
+// statusRecorder captures the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (r *statusRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := r.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, fmt.Errorf("response writer does not support hijacking")
+	}
+	return h.Hijack()
+}
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"goction/internal/config"
+	"goction/internal/logging"
+
+	"github.com/sirupsen/logrus"
+)
+
+// configPollInterval is how often the configuration file is checked for changes
+const configPollInterval = 2 * time.Second
+
+// cfg returns the configuration currently applied
+func (s *Server) cfg() *config.Config {
+	return s.config.Load()
+}
+
+// watchConfig reloads the configuration on SIGHUP and when the configuration file changes, until stop is closed
+func (s *Server) watchConfig(stop <-chan struct{}) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	ticker := time.NewTicker(configPollInterval)
+	defer ticker.Stop()
+
+	modTime := fileModTime(s.cfg().Path())
+	for {
+		select {
+		case <-stop:
+			return
+		case <-hup:
+			s.reloadConfig("signal")
+		case <-ticker.C:
+			if current := fileModTime(s.cfg().Path()); !current.Equal(modTime) {
+				modTime = current
+				s.reloadConfig("file change")
+			}
+		}
+	}
+}
+
+func fileModTime(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// reloadConfig loads the configuration again and applies the changes that do not require a restart.
+// An invalid configuration is rejected as a whole and the current one is kept.
+func (s *Server) reloadConfig(trigger string) {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	entry := s.logger.WithFields(logrus.Fields{"event": "config_reload", "trigger": trigger})
+
+	current := s.cfg()
+	next, err := current.Reload()
+	if err == nil {
+		err = next.ValidateServer()
+	}
+	if err != nil {
+		entry.WithError(err).Error("Configuration reload failed, keeping the current configuration")
+		return
+	}
+
+	changes := current.Diff(next)
+	if len(changes) == 0 {
+		entry.Info("Configuration reloaded without changes")
+		return
+	}
+
+	current.KeepRestartValues(next)
+	if err := s.alerts.Reload(next); err != nil {
+		entry.WithError(err).Error("Configuration reload failed, keeping the current configuration")
+		return
+	}
+	s.config.Store(next)
+	s.runner.SetConfig(next)
+	s.notifier.SetConfig(next)
+	if err := logging.Apply(s.logger, next); err != nil {
+		entry.WithError(err).Error("Failed to apply the logging configuration")
+	}
+
+	var applied, pending []string
+	for _, change := range changes {
+		diff := fmt.Sprintf("%s: %q -> %q", change.Key, change.Old, change.New)
+		if change.Restart {
+			pending = append(pending, diff)
+		} else {
+			applied = append(applied, diff)
+		}
+	}
+
+	entry.WithFields(logrus.Fields{
+		"applied":         applied,
+		"pending_restart": pending,
+	}).Info("Configuration reloaded")
+	for _, diff := range pending {
+		entry.Warnf("Configuration change requires a restart: %s", diff)
+	}
+}
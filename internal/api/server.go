@@ -1,79 +1,96 @@
 package api
 
 import (
+	"context"
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
-	"path/filepath"
-	"plugin"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"goction/internal/alerting"
 	"goction/internal/api/dashboard"
+	"goction/internal/api/dashboard/assets"
 	"goction/internal/config"
+	"goction/internal/logging"
+	"goction/internal/notify"
+	"goction/internal/runner"
 	"goction/internal/stats"
+	"goction/internal/sysmetrics"
 
+	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 	"github.com/gorilla/sessions"
 	"github.com/sirupsen/logrus"
 )
 
-type GoctionFunc func(...string) (string, error)
+type contextKey string
+
+// callerKey holds the name of the API key that authenticated the request
+const callerKey contextKey = "caller"
 
 type Server struct {
-	config        *config.Config
-	router        *mux.Router
-	logger        *logrus.Logger
-	stats         *stats.Manager
-	goctionsCache map[string]GoctionFunc
-	sessionStore  *sessions.CookieStore
+	config       atomic.Pointer[config.Config]
+	reloadMu     sync.Mutex
+	router       *mux.Router
+	logger       *logrus.Logger
+	stats        *stats.Manager
+	runner       *runner.Runner
+	alerts       *alerting.Manager
+	notifier     *notify.Dispatcher
+	sessionStore *sessions.CookieStore
+	metrics      *serverMetrics
+	collector    *sysmetrics.Collector
+	events       *eventHub
 }
 
-func NewServer(cfg *config.Config) (*Server, error) {
-	logger := logrus.New()
-	if err := setupLogger(logger, cfg); err != nil {
-		return nil, fmt.Errorf("failed to setup logger: %w", err)
-	}
-
+// NewServer creates the API server, logging with the logger shared with the commands
+func NewServer(cfg *config.Config, logger *logrus.Logger) (*Server, error) {
 	statsManager, err := stats.NewManager(cfg.StatsFile)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create stats manager: %w", err)
 	}
 
-	s := &Server{
-		config:        cfg,
-		router:        mux.NewRouter(),
-		logger:        logger,
-		stats:         statsManager,
-		goctionsCache: make(map[string]GoctionFunc),
-		sessionStore:  sessions.NewCookieStore([]byte("secret-key")), // Use a secure, random key in production
+	serverMetrics, err := newServerMetrics()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create metrics: %w", err)
 	}
-	s.routes()
-	return s, nil
-}
 
-func setupLogger(logger *logrus.Logger, cfg *config.Config) error {
-	// Ensure log directory exists
-	logDir := filepath.Dir(cfg.LogFile)
-	if err := os.MkdirAll(logDir, 0755); err != nil {
-		return fmt.Errorf("failed to create log directory: %w", err)
-	}
+	goctionRunner := runner.New(cfg, statsManager, logger)
+	goctionRunner.AddObserver(serverMetrics)
 
-	// Open log file
-	logFile, err := os.OpenFile(cfg.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	notifier := notify.NewDispatcher(cfg, logger)
+	goctionRunner.AddObserver(notifier)
+
+	events := newEventHub()
+	goctionRunner.AddObserver(events)
+
+	alertManager, err := alerting.NewManager(cfg, statsManager, goctionRunner, logger)
 	if err != nil {
-		return fmt.Errorf("failed to open log file: %w", err)
+		return nil, fmt.Errorf("invalid alerting configuration: %w", err)
 	}
 
-	// Configure logrus
-	logger.SetOutput(logFile)
-	logger.SetLevel(logrus.InfoLevel) // Ajustez le niveau de log selon vos besoins
-	logger.SetFormatter(&logrus.TextFormatter{
-		FullTimestamp: true,
-	})
-
-	return nil
+	s := &Server{
+		router:       mux.NewRouter(),
+		logger:       logger,
+		stats:        statsManager,
+		runner:       goctionRunner,
+		alerts:       alertManager,
+		notifier:     notifier,
+		sessionStore: newSessionStore(cfg.SessionKey),
+		metrics:      serverMetrics,
+		collector:    sysmetrics.NewCollector(systemSampleInterval),
+		events:       events,
+	}
+	s.config.Store(cfg)
+	s.routes()
+	return s, nil
 }
 
 func (s *Server) routes() {
@@ -83,16 +100,54 @@ func (s *Server) routes() {
 	api := s.router.PathPrefix("/api").Subrouter()
 	api.HandleFunc("/goctions/{goction}", s.authMiddleware(s.handleExecuteGoction)).Methods("POST")
 	api.HandleFunc("/goctions", s.authMiddleware(s.handleListGoctions)).Methods("GET")
+	api.HandleFunc("/goctions", s.authMiddleware(s.handleCreateGoction)).Methods("POST")
+	api.HandleFunc("/goctions/{goction}", s.authMiddleware(s.handleDeleteGoction)).Methods("DELETE")
+	api.HandleFunc("/goctions/{goction}/archive", s.authMiddleware(s.handleUploadArchive)).Methods("PUT")
+	api.HandleFunc("/goctions/{goction}/files/{file}", s.authMiddleware(s.handleUploadFile)).Methods("PUT")
+	api.HandleFunc("/goctions/{goction}/build", s.authMiddleware(s.handleBuildGoction)).Methods("POST")
+	api.HandleFunc("/goctions/{goction}/disable", s.authMiddleware(s.handleSetDisabled(true))).Methods("POST")
+	api.HandleFunc("/goctions/{goction}/enable", s.authMiddleware(s.handleSetDisabled(false))).Methods("POST")
 	api.HandleFunc("/goctions/{goction}/info", s.authMiddleware(s.handleGetGoctionInfo)).Methods("GET")
 	api.HandleFunc("/goctions/{goction}/history", s.authMiddleware(s.handleGetGoctionHistory)).Methods("GET")
+	api.HandleFunc("/executions", s.authMiddleware(s.handleListExecutions)).Methods("GET")
+	api.HandleFunc("/executions/{id}/logs", s.authMiddleware(s.handleGetExecutionLogs)).Methods("GET")
+	api.HandleFunc("/logs", s.authMiddleware(s.handleGetLogs)).Methods("GET")
+	api.HandleFunc("/stats/export", s.authMiddleware(s.handleExportStats)).Methods("GET")
+	api.HandleFunc("/stats/timeseries", s.authMiddleware(s.handleStatsTimeseries)).Methods("GET")
+	api.HandleFunc("/alerts", s.authMiddleware(s.handleListAlerts)).Methods("GET")
+	api.HandleFunc("/notifications/deliveries", s.authMiddleware(s.handleListDeliveries)).Methods("GET")
+
+	// Metrics route
+	s.router.HandleFunc("/metrics", s.metricsAuthMiddleware(s.handleMetrics)).Methods("GET")
 
 	// Dashboard routes
-	s.router.HandleFunc("/login", dashboard.LoginHandler(s.config, s.sessionStore)).Methods("GET", "POST")
+	s.router.HandleFunc("/login", dashboard.LoginHandler(s.cfg, s.sessionStore)).Methods("GET", "POST")
 	s.router.HandleFunc("/logout", dashboard.LogoutHandler(s.sessionStore)).Methods("GET")
-	s.router.HandleFunc("/", s.authSessionMiddleware(dashboard.DashboardHandler(s.config, s.stats))).Methods("GET")
+	s.router.HandleFunc("/", s.authSessionMiddleware(dashboard.DashboardHandler(s.cfg, s.stats, s.collector.Latest))).Methods("GET")
+	s.router.HandleFunc("/goctions/{goction}/history", s.authSessionMiddleware(dashboard.HistoryHandler(s.stats))).Methods("GET")
+	s.router.HandleFunc("/executions/{id}", s.authSessionMiddleware(dashboard.ExecutionHandler(s.cfg, s.stats, s.runner.Logs))).Methods("GET")
+	s.router.HandleFunc("/executions/{id}/logs", s.authSessionMiddleware(dashboard.ExecutionLogsRedirectHandler())).Methods("GET")
+	s.router.HandleFunc("/executions/{id}/rerun", s.authSessionMiddleware(dashboard.RerunHandler(s.cfg, s.stats, s.sessionStore, s.runner.Run))).Methods("POST")
+	s.router.HandleFunc("/goctions/{goction}/edit", s.authSessionMiddleware(dashboard.EditorHandler(s.cfg))).Methods("GET")
+	s.router.HandleFunc("/goctions/{goction}/source", s.authSessionMiddleware(dashboard.SaveSourceHandler(s.cfg, s.sessionStore))).Methods("POST")
+	s.router.HandleFunc("/goctions/{goction}/revisions/{id}/restore", s.authSessionMiddleware(dashboard.RestoreRevisionHandler(s.cfg, s.sessionStore))).Methods("POST")
+	s.router.HandleFunc("/goctions/{goction}/run", s.authSessionMiddleware(dashboard.RunHandler(s.cfg, s.sessionStore, s.runner.Run))).Methods("POST")
+	s.router.HandleFunc("/events", s.authSessionMiddleware(s.handleDashboardEvents)).Methods("GET")
+	s.router.HandleFunc("/stats/timeseries", s.authSessionMiddleware(s.handleStatsTimeseries)).Methods("GET")
+	s.router.HandleFunc("/logs/stream", s.authSessionMiddleware(dashboard.LogStreamHandler(s.cfg))).Methods("GET")
+	s.router.HandleFunc("/logs/download", s.authSessionMiddleware(dashboard.LogDownloadHandler(s.cfg))).Methods("GET")
+
+	// Serve the dashboard assets embedded in the binary
+	s.router.PathPrefix(assets.Prefix).Handler(assets.Handler()).Methods("GET", "HEAD")
+}
 
-	// Serve static files
-	s.router.PathPrefix("/static/").Handler(http.StripPrefix("/static/", http.FileServer(http.Dir("./internal/api/dashboard/static"))))
+// newSessionStore returns the store of dashboard sessions, whose cookies are signed with key.
+// The key comes from the configuration, see Config.ValidateServer, so that sessions cannot be forged.
+func newSessionStore(key string) *sessions.CookieStore {
+	store := sessions.NewCookieStore([]byte(key))
+	store.Options.HttpOnly = true
+	store.Options.SameSite = http.SameSiteLaxMode
+	return store
 }
 
 func (s *Server) authSessionMiddleware(next http.HandlerFunc) http.HandlerFunc {
@@ -107,137 +162,211 @@ func (s *Server) authSessionMiddleware(next http.HandlerFunc) http.HandlerFunc {
 }
 
 func (s *Server) Start() error {
-	s.logger.Infof("Server starting on :%d", s.config.Port)
-	return http.ListenAndServe(fmt.Sprintf(":%d", s.config.Port), s.router)
+	stop := make(chan struct{})
+	defer close(stop)
+	go s.alerts.Run(stop)
+	go s.collector.Run(stop)
+	go s.watchConfig(stop)
+
+	s.logger.Infof("Server starting on :%d", s.cfg().Port)
+	return http.ListenAndServe(fmt.Sprintf(":%d", s.cfg().Port), s.router)
 }
 
+// RequestIDHeader carries the ID identifying a request in the logs. A valid ID sent by the client is kept.
+const RequestIDHeader = "X-Request-ID"
+
 func (s *Server) loggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
-		next.ServeHTTP(w, r)
-		s.logger.WithFields(logrus.Fields{
+		requestID := r.Header.Get(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.New().String()
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+		entry := s.logger.WithField(logging.FieldRequestID, requestID)
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r.WithContext(logging.WithEntry(r.Context(), entry)))
+		duration := time.Since(start)
+		s.metrics.observeHTTP(r, recorder.status, duration)
+		entry.WithFields(logrus.Fields{
 			"method":   r.Method,
 			"path":     r.URL.Path,
-			"duration": time.Since(start),
+			"status":   recorder.status,
+			"duration": duration,
 		}).Info("Request handled")
 	})
 }
 
+// log returns the logger of a request, which adds its request ID to every line
+func (s *Server) log(r *http.Request) *logrus.Entry {
+	return logging.FromContext(r.Context(), s.logger)
+}
+
+// validRequestID accepts IDs of up to 128 letters, digits, dashes, underscores and dots
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.') {
+			return false
+		}
+	}
+	return true
+}
+
 func (s *Server) authMiddleware(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		token := r.Header.Get("X-API-Token")
-		if !strings.EqualFold(strings.TrimSpace(token), strings.TrimSpace(s.config.APIToken)) {
+		caller, ok := s.authenticate(r.Header.Get("X-API-Token"))
+		if !ok {
 			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
 		}
-		next.ServeHTTP(w, r)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), callerKey, caller)))
 	}
 }
 
+// authenticate returns the name of the API key matching token: "default" for the main API token,
+// or the name of one of the configured API keys
+func (s *Server) authenticate(token string) (string, bool) {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", false
+	}
+	cfg := s.cfg()
+	if subtle.ConstantTimeCompare([]byte(token), []byte(strings.TrimSpace(cfg.APIToken))) == 1 {
+		return "default", true
+	}
+	for name, key := range cfg.APIKeys {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(key)) == 1 {
+			return name, true
+		}
+	}
+	return "", false
+}
+
 func (s *Server) handleExecuteGoction(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	goctionName := vars["goction"]
-
-	goction, err := s.getGoction(goctionName)
-	if err != nil {
-		s.logger.WithError(err).Errorf("Failed to get goction: %s", goctionName)
-		http.Error(w, fmt.Sprintf("Goction not found: %v", err), http.StatusNotFound)
-		return
-	}
+	entry := s.log(r).WithField(logging.FieldGoction, goctionName)
 
 	var requestBody struct {
 		Args []string `json:"args"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
-		s.logger.WithError(err).Error("Failed to decode request body")
+		entry.WithError(err).Error("Failed to decode request body")
 		http.Error(w, fmt.Sprintf("Invalid request body: %v", err), http.StatusBadRequest)
 		return
 	}
 
-	start := time.Now()
-	result, err := goction(requestBody.Args...)
-	duration := time.Since(start)
-
-	s.stats.RecordExecution(goctionName, duration, err == nil, result)
+	caller, _ := r.Context().Value(callerKey).(string)
+	record, err := s.runner.Run(runner.Request{
+		Goction: goctionName,
+		Args:    requestBody.Args,
+		Caller:  caller,
+		Trigger: stats.TriggerAPI,
+	})
 
+	var loadErr *runner.LoadError
+	if errors.As(err, &loadErr) {
+		entry.WithError(err).Error("Failed to load goction")
+		http.Error(w, fmt.Sprintf("Goction not found: %v", err), http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, runner.ErrDisabled) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if errors.Is(err, runner.ErrTimeout) {
+		entry.WithError(err).WithField(logging.FieldExecutionID, record.ID).Error("Goction execution timed out")
+		http.Error(w, fmt.Sprintf("Goction execution failed: %v", err), http.StatusGatewayTimeout)
+		return
+	}
 	if err != nil {
-		s.logger.WithError(err).Errorf("Goction execution failed: %s", goctionName)
+		entry.WithError(err).WithField(logging.FieldExecutionID, record.ID).Error("Goction execution failed")
 		http.Error(w, fmt.Sprintf("Goction execution failed: %v", err), http.StatusInternalServerError)
 		return
 	}
 
-	s.logger.WithFields(logrus.Fields{
-		"goction":  goctionName,
-		"duration": duration,
+	entry.WithFields(logrus.Fields{
+		logging.FieldExecutionID: record.ID,
+		"duration":               record.Duration,
 	}).Info("Goction executed successfully")
 
-	json.NewEncoder(w).Encode(map[string]string{"result": result})
+	w.Header().Set("X-Goction-Execution-ID", record.ID)
+	json.NewEncoder(w).Encode(map[string]string{"result": record.Result, "execution_id": record.ID})
 }
 
-func (s *Server) handleListGoctions(w http.ResponseWriter, r *http.Request) {
-	goctions, err := s.listGoctions()
-	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to list goctions: %v", err), http.StatusInternalServerError)
-		return
+func (s *Server) handleListAlerts(w http.ResponseWriter, r *http.Request) {
+	alerts := s.alerts.Alerts()
+	if state := r.URL.Query().Get("state"); state != "" {
+		filtered := alerts[:0]
+		for _, alert := range alerts {
+			if alert.State == state {
+				filtered = append(filtered, alert)
+			}
+		}
+		alerts = filtered
 	}
 
-	json.NewEncoder(w).Encode(map[string][]string{"goctions": goctions})
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string][]alerting.Alert{"alerts": alerts})
 }
 
-func (s *Server) handleGetGoctionInfo(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	goctionName := vars["goction"]
+func (s *Server) handleListDeliveries(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := notify.DeliveryFilter{
+		Goction:     query.Get("goction"),
+		ExecutionID: query.Get("execution_id"),
+		Status:      query.Get("status"),
+		Limit:       100,
+	}
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n <= 0 {
+			http.Error(w, fmt.Sprintf("Invalid limit %q", limit), http.StatusBadRequest)
+			return
+		}
+		filter.Limit = n
+	}
 
-	info, err := s.getGoctionInfo(goctionName)
+	deliveries, err := s.notifier.Log().List(filter)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to get goction info: %v", err), http.StatusNotFound)
+		http.Error(w, fmt.Sprintf("Failed to read deliveries: %v", err), http.StatusInternalServerError)
 		return
 	}
 
-	json.NewEncoder(w).Encode(info)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string][]notify.Delivery{"deliveries": deliveries})
 }
 
-func (s *Server) handleGetGoctionHistory(w http.ResponseWriter, r *http.Request) {
-	vars := mux.Vars(r)
-	goctionName := vars["goction"]
-
-	history, err := s.getGoctionHistory(goctionName)
+func (s *Server) handleListGoctions(w http.ResponseWriter, r *http.Request) {
+	goctions, err := s.listGoctions()
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to get goction history: %v", err), http.StatusNotFound)
+		http.Error(w, fmt.Sprintf("Failed to list goctions: %v", err), http.StatusInternalServerError)
 		return
 	}
 
-	json.NewEncoder(w).Encode(map[string][]stats.ExecutionRecord{"history": history})
+	json.NewEncoder(w).Encode(map[string][]string{"goctions": goctions})
 }
 
-func (s *Server) getGoction(name string) (GoctionFunc, error) {
-	if goction, ok := s.goctionsCache[name]; ok {
-		return goction, nil
-	}
-
-	goctionPath := filepath.Join(s.config.GoctionsDir, name, name+".so")
-	plug, err := plugin.Open(goctionPath)
-	if err != nil {
-		return nil, fmt.Errorf("could not open goction plugin: %w", err)
-	}
+func (s *Server) handleGetGoctionInfo(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	goctionName := vars["goction"]
 
-	sym, err := plug.Lookup(strings.Title(name))
+	info, err := s.getGoctionInfo(goctionName)
 	if err != nil {
-		return nil, fmt.Errorf("could not find goction symbol: %w", err)
-	}
-
-	goction, ok := sym.(func(...string) (string, error))
-	if !ok {
-		return nil, fmt.Errorf("unexpected type from module symbol")
+		http.Error(w, fmt.Sprintf("Failed to get goction info: %v", err), http.StatusNotFound)
+		return
 	}
 
-	s.goctionsCache[name] = goction
-	return goction, nil
+	json.NewEncoder(w).Encode(info)
 }
 
 func (s *Server) listGoctions() ([]string, error) {
-	files, err := os.ReadDir(s.config.GoctionsDir)
+	files, err := os.ReadDir(s.cfg().GoctionsDir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read goctions directory: %w", err)
 	}
@@ -271,11 +400,3 @@ func (s *Server) getGoctionInfo(name string) (map[string]interface{}, error) {
 
 	return info, nil
 }
-
-func (s *Server) getGoctionHistory(name string) ([]stats.ExecutionRecord, error) {
-	history := s.stats.GetExecutionHistory(name)
-	if history == nil {
-		return nil, fmt.Errorf("no history found for goction: %s", name)
-	}
-	return history, nil
-}
@@ -1,24 +1,38 @@
 package cmd
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
+	"net/http"
+	"net/url"
 	"os"
 	"os/exec"
+	"os/signal"
+	"os/user"
 	"path/filepath"
-	"plugin"
 	"runtime"
+	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"goction/internal/config"
+	"goction/internal/execlog"
+	"goction/internal/goctions"
+	"goction/internal/logging"
+	"goction/internal/notify"
+	"goction/internal/runner"
 	"goction/internal/stats"
-	"goction/pkg/goctionutil"
 
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/shirou/gopsutil/v3/cpu"
 	"github.com/shirou/gopsutil/v3/mem"
+	"github.com/sirupsen/logrus"
 )
 
 // CreateNewGoction creates a new goction file
@@ -28,23 +42,8 @@ func CreateNewGoction(args []string, cfg *config.Config) error {
 	}
 	name := args[0]
 
-	goctionDir := filepath.Join(cfg.GoctionsDir, name)
-	if err := os.MkdirAll(goctionDir, 0775); err != nil {
-		return fmt.Errorf("failed to create goction directory: %w", err)
-	}
-
-	filename := filepath.Join(goctionDir, "main.go")
-	content := goctionutil.GenerateGoctionTemplate(name)
-
-	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
-		return fmt.Errorf("failed to write goction file: %w", err)
-	}
-
-	// Initialize go.mod
-	cmd := exec.Command("go", "mod", "init", fmt.Sprintf("goction/%s", name))
-	cmd.Dir = goctionDir
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to initialize go.mod: %w", err)
+	if err := goctions.Create(context.Background(), cfg.GoctionsDir, name, ""); err != nil {
+		return err
 	}
 
 	fmt.Printf("New goction '%s' created successfully\n", name)
@@ -124,43 +123,80 @@ func UpdateGoction(args []string, cfg *config.Config) error {
 	}
 	name := args[0]
 
-	goctionDir := filepath.Join(cfg.GoctionsDir, name)
-	if _, err := os.Stat(goctionDir); os.IsNotExist(err) {
+	result, err := goctions.Build(context.Background(), cfg.GoctionsDir, name, os.Stderr)
+	if errors.Is(err, goctions.ErrNotFound) {
 		return fmt.Errorf("goction '%s' does not exist", name)
 	}
-
-	cmd := exec.Command("go", "build", "-buildmode=plugin", "-o",
-		filepath.Join(goctionDir, name+".so"), ".")
-	cmd.Dir = goctionDir
-	cmd.Env = append(os.Environ(), "PATH="+os.Getenv("PATH")+":/usr/local/go/bin")
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to build goction: %w", err)
+	if err != nil {
+		return err
+	}
+	if !result.Success {
+		return fmt.Errorf("failed to build goction '%s'", name)
 	}
 
 	fmt.Printf("Goction '%s' updated successfully\n", name)
 	return nil
 }
 
+// RemoveGoction deletes a goction and its statistics
+func RemoveGoction(args []string, cfg *config.Config, statsManager *stats.Manager) error {
+	if len(args) < 1 {
+		return fmt.Errorf("usage: goction remove <goction-name>")
+	}
+	name := args[0]
+
+	err := goctions.Remove(cfg.GoctionsDir, name)
+	if errors.Is(err, goctions.ErrNotFound) {
+		return fmt.Errorf("goction '%s' does not exist", name)
+	}
+	if err != nil {
+		return err
+	}
+	if err := statsManager.Reset(name); err != nil {
+		return fmt.Errorf("goction removed but failed to delete its statistics: %w", err)
+	}
+
+	fmt.Printf("Goction '%s' removed successfully\n", name)
+	return nil
+}
+
 // ShowStats displays statistics for goctions
-func ShowStats(args []string, statsManager *stats.Manager) error {
+func ShowStats(args []string, cfg *config.Config, statsManager *stats.Manager) error {
 	if len(args) == 0 {
-		return showAllStats(statsManager)
+		return showAllStats(cfg, statsManager)
+	}
+	switch args[0] {
+	case "export":
+		return ExportStats(args[1:], statsManager)
+	case "reset":
+		return ResetStats(args[1:], cfg, statsManager)
+	case "import":
+		return ImportStats(args[1:], statsManager)
 	}
 	return showSpecificStats(args[0], statsManager)
 }
 
-func showAllStats(statsManager *stats.Manager) error {
+// showAllStats displays the statistics of the installed goctions, and how many removed goctions still have some
+func showAllStats(cfg *config.Config, statsManager *stats.Manager) error {
 	allStats := statsManager.GetAllStats()
+	orphans := 0
+	for name := range allStats {
+		if !goctionExists(cfg, name) {
+			delete(allStats, name)
+			orphans++
+		}
+	}
+
 	if len(allStats) == 0 {
 		fmt.Println("No statistics available.")
-		return nil
+	} else {
+		fmt.Println("Goction Statistics:")
+		for name, stats := range allStats {
+			printGoctionStats(name, stats)
+		}
 	}
-
-	fmt.Println("Goction Statistics:")
-	for name, stats := range allStats {
-		printGoctionStats(name, stats)
+	if orphans > 0 {
+		fmt.Printf("%d removed goctions still have statistics; delete them with 'goction stats reset --orphans'.\n", orphans)
 	}
 	return nil
 }
@@ -199,46 +235,57 @@ func ShowDashboard(cfg *config.Config) error {
 	return nil
 }
 
-func RunGoction(name string, args []string, cfg *config.Config) error {
-	goctionPath := filepath.Join(cfg.GoctionsDir, name, name+".so")
-
-	if _, err := os.Stat(goctionPath); os.IsNotExist(err) {
-		return fmt.Errorf("goction plugin not found. Please run 'goction update %s' to build the plugin", name)
-	}
-
-	plug, err := plugin.Open(goctionPath)
-	if err != nil {
-		return fmt.Errorf("could not open goction plugin: %w", err)
-	}
-
-	sym, err := plug.Lookup(strings.Title(name))
+// notificationWaitTimeout bounds how long goction run waits for notifications to be delivered
+const notificationWaitTimeout = 30 * time.Second
+
+// RunGoction executes a goction from the command line and records the execution
+func RunGoction(name string, args []string, cfg *config.Config, statsManager *stats.Manager, logger *logrus.Logger) error {
+	goctionRunner := runner.New(cfg, statsManager, logger)
+	notifier := notify.NewDispatcher(cfg, logger)
+	goctionRunner.AddObserver(notifier)
+	// Deliver notifications before the process exits, without hanging on unreachable endpoints
+	defer func() {
+		if !notifier.Wait(notificationWaitTimeout) {
+			logger.Warnf("Notifications still pending after %s were abandoned", notificationWaitTimeout)
+		}
+	}()
+
+	record, err := goctionRunner.Run(runner.Request{
+		Goction: name,
+		Args:    args,
+		Caller:  currentUser(),
+		Trigger: stats.TriggerCLI,
+		Output:  os.Stdout,
+	})
+	entry := logger.WithField(logging.FieldGoction, name)
+	var loadErr *runner.LoadError
+	if errors.As(err, &loadErr) {
+		entry.WithError(err).Error("Failed to load goction")
+		return err
+	}
+	if errors.Is(err, runner.ErrDisabled) {
+		return err
+	}
+	entry = entry.WithField(logging.FieldExecutionID, record.ID)
 	if err != nil {
-		return fmt.Errorf("could not find goction symbol: %w", err)
-	}
-
-	goction, ok := sym.(func(...string) (string, error))
-	if !ok {
-		return fmt.Errorf("unexpected type from module symbol")
+		entry.WithError(err).Error("Goction execution failed")
+		return fmt.Errorf("goction execution %s failed: %w", record.ID, err)
 	}
+	entry.WithField("duration", record.Duration).Info("Goction executed successfully")
 
-	start := time.Now()
-	result, err := goction(args...)
-	duration := time.Since(start)
+	fmt.Printf("Goction '%s' executed successfully in %v\n", name, record.Duration)
+	fmt.Printf("Execution ID: %s\n", record.ID)
+	fmt.Printf("Result: %s\n", record.Result)
 
-	statsManager, err := stats.NewManager(cfg.StatsFile)
-	if err != nil {
-		return fmt.Errorf("failed to create stats manager: %w", err)
-	}
-	statsManager.RecordExecution(name, duration, err == nil, result)
+	return nil
+}
 
-	if err != nil {
-		return fmt.Errorf("goction execution failed: %w", err)
+// currentUser returns the name of the user running the CLI
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
 	}
-
-	fmt.Printf("Goction '%s' executed successfully in %v\n", name, duration)
-	fmt.Printf("Result: %s\n", result)
-
-	return nil
+	return os.Getenv("USER")
 }
 
 // ShowToken displays the current API token
@@ -283,7 +330,7 @@ func renderActionTable(cfg *config.Config, sectionStyle lipgloss.Style) {
 
 func renderRecentLogs(cfg *config.Config, sectionStyle, infoStyle lipgloss.Style) {
 	fmt.Println(sectionStyle.Render("Recent Logs"))
-	logs, err := getRecentLogs(cfg.LogFile, 5)
+	logs, err := logging.Tail(cfg.LogFile, 5)
 	if err != nil {
 		fmt.Printf("%s Error reading logs: %v\n", infoStyle.Render("•"), err)
 	} else {
@@ -428,80 +475,207 @@ func printGoctionStats(name string, stats *stats.GoctionStats) {
 	}
 }
 
-func getRecentLogs(logFile string, numLines int) ([]string, error) {
-	file, err := os.Open(logFile)
-	if err != nil {
-		return nil, err
+// ConfigView displays the current configuration and where each value came from, with secrets masked
+func ConfigView(cfg *config.Config) error {
+	fmt.Println("Current Goction Configuration:")
+	fmt.Printf("Config File: %s\n\n", cfg.Path())
+
+	keyStyle := lipgloss.NewStyle().Bold(true)
+	sourceStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
+	for _, field := range cfg.Fields() {
+		source := field.Source
+		if source == config.SourceEnv {
+			source += " " + config.EnvName(field.Key)
+		}
+		value := field.Value
+		if config.IsSecretKey(field.Key) {
+			value = config.MaskSecret(value)
+		}
+		fmt.Printf("%s = %s %s\n", keyStyle.Render(field.Key), value, sourceStyle.Render("("+source+")"))
 	}
-	defer file.Close()
+	return nil
+}
 
-	// Get the file size
-	stat, err := file.Stat()
+// ConfigGet prints the value of a configuration key
+func ConfigGet(args []string, cfg *config.Config) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: goction config get <key>")
+	}
+	value, err := cfg.Get(args[0])
 	if err != nil {
-		return nil, err
+		return err
 	}
+	fmt.Println(value)
+	return nil
+}
 
-	// Calculate the size of the buffer we'll need
-	bufferSize := int(stat.Size())
-	if bufferSize > 1024*1024 { // limit to 1MB
-		bufferSize = 1024 * 1024
+// ConfigSet validates a new value for a configuration key and writes it to the configuration file
+func ConfigSet(args []string, cfg *config.Config) error {
+	if len(args) != 2 {
+		return fmt.Errorf("usage: goction config set <key> <value>")
 	}
+	key, value := args[0], args[1]
 
-	// Read the file from the end
-	buffer := make([]byte, bufferSize)
-	start := stat.Size() - int64(bufferSize)
-	if start < 0 {
-		start = 0
+	// Validate the configuration the value will actually run with
+	if err := cfg.Set(key, value); err != nil {
+		return err
 	}
-	_, err = file.ReadAt(buffer, start)
-	if err != nil && err != io.EOF {
-		return nil, err
+	if err := cfg.Validate(); err != nil {
+		return err
 	}
 
-	// Find the last numLines lines
-	lines := strings.Split(string(buffer), "\n")
-	if len(lines) > numLines {
-		lines = lines[len(lines)-numLines:]
+	// Write the file alone so that environment and command line overrides are not persisted
+	fileCfg, err := config.LoadFile(cfg.Path())
+	if err != nil {
+		return err
 	}
-
-	// Trim any empty lines at the start
-	for len(lines) > 0 && lines[0] == "" {
-		lines = lines[1:]
+	if err := fileCfg.Set(key, value); err != nil {
+		return err
+	}
+	if err := fileCfg.Save(); err != nil {
+		return err
 	}
 
-	return lines, nil
+	fmt.Printf("%s updated in %s\n", key, cfg.Path())
+	if source := cfg.Source(key); source == config.SourceEnv || source == config.SourceFlag {
+		fmt.Printf("Note: %s is currently overridden by %s\n", key, source)
+	}
+	return nil
 }
 
-// ConfigView displays the current configuration
-func ConfigView(cfg *config.Config) error {
-	fmt.Println("Current Goction Configuration:")
-	fmt.Printf("API Token: %s\n", cfg.APIToken)
-	fmt.Printf("Goctions Directory: %s\n", cfg.GoctionsDir)
-	fmt.Printf("Server Port: %d\n", cfg.Port)
-	fmt.Printf("Log File: %s\n", cfg.LogFile)
-	fmt.Printf("Stats File: %s\n", cfg.StatsFile)
+// ConfigValidate checks the configuration and lists every problem found
+func ConfigValidate(cfg *config.Config) error {
+	err := cfg.ValidateServer()
+	var validationErr *config.ValidationError
+	if errors.As(err, &validationErr) {
+		fmt.Printf("Configuration %s is invalid:\n", cfg.Path())
+		for _, problem := range validationErr.Problems {
+			fmt.Printf("  - %s\n", problem)
+		}
+		return fmt.Errorf("%d configuration problem(s) found", len(validationErr.Problems))
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Configuration %s is valid.\n", cfg.Path())
 	return nil
 }
 
 // ConfigReset resets the configuration to default values
 func ConfigReset(cfg *config.Config) error {
-	if err := config.Reset(); err != nil {
+	if err := config.Reset(cfg.Path()); err != nil {
 		return fmt.Errorf("failed to reset configuration: %w", err)
 	}
 	fmt.Println("Configuration has been reset to default values.")
 	return nil
 }
 
-// ShowLogs displays recent log entries
-func ShowLogs(cfg *config.Config) error {
-	logs, err := getRecentLogs(cfg.LogFile, 20) // Show last 20 log entries
+// ShowLogs displays recent log entries, optionally filtered, and follows new ones with -f
+func ShowLogs(args []string, cfg *config.Config) error {
+	fs := flag.NewFlagSet("logs", flag.ContinueOnError)
+	executionID := fs.String("execution", "", "show the output of this execution instead of the goction log")
+	var follow bool
+	fs.BoolVar(&follow, "follow", false, "keep printing new entries, across log rotations")
+	fs.BoolVar(&follow, "f", false, "shorthand for --follow")
+	lines := fs.Int("n", 20, "number of past entries to show")
+	goction := fs.String("goction", "", "only show entries about this goction")
+	level := fs.String("level", "", "only show entries of this level or more severe (e.g. warn)")
+	since := fs.String("since", "", "only show entries since a time (RFC 3339, YYYY-MM-DD or a duration such as 1h or 7d)")
+	grep := fs.String("grep", "", "only show entries matching this regular expression")
+	asJSON := fs.Bool("json", false, "print entries as JSON lines")
+	remote := fs.String("remote", "", "read the logs of the server at this URL (e.g. http://host:8080)")
+	token := fs.String("token", "", "API token for --remote (defaults to the configured token)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *executionID != "" {
+		return showExecutionLogs(*executionID, cfg)
+	}
+
+	filter, err := logging.NewFilter(*goction, *level, *since, *grep)
 	if err != nil {
+		return err
+	}
+	print := func(entry logging.Entry) {
+		if *asJSON {
+			data, _ := json.Marshal(entry)
+			fmt.Println(string(data))
+		} else {
+			fmt.Println(entry.Raw)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *remote != "" {
+		if *token == "" {
+			*token = cfg.APIToken
+		}
+		query := url.Values{"lines": {strconv.Itoa(*lines)}, "follow": {strconv.FormatBool(follow)},
+			"goction": {*goction}, "level": {*level}, "since": {*since}, "grep": {*grep}}
+		return followRemoteLogs(ctx, strings.TrimSuffix(*remote, "/")+"/api/logs?"+query.Encode(), *token, print)
+	}
+
+	entries, err := logging.Recent(cfg.LogFile, filter, *lines)
+	if err != nil && !(follow && os.IsNotExist(err)) {
 		return fmt.Errorf("failed to read logs: %w", err)
 	}
+	for _, entry := range entries {
+		print(entry)
+	}
+	if follow {
+		return logging.FollowEntries(ctx, cfg.LogFile, filter, print)
+	}
+	return nil
+}
+
+// followRemoteLogs prints the JSON lines streamed by the logs endpoint of a server until it ends the response or ctx is done
+func followRemoteLogs(ctx context.Context, endpoint, token string, print func(logging.Entry)) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("invalid remote URL: %w", err)
+	}
+	req.Header.Set("X-API-Token", token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to connect to the server: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("server returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
 
-	fmt.Println("Recent logs:")
-	for _, log := range logs {
-		fmt.Println(log)
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var entry logging.Entry
+		if err := decoder.Decode(&entry); err != nil {
+			if err == io.EOF || ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to read logs from the server: %w", err)
+		}
+		print(entry)
+	}
+}
+
+// showExecutionLogs prints what an execution printed and logged
+func showExecutionLogs(id string, cfg *config.Config) error {
+	lines, err := execlog.Open(cfg.ExecutionLogsPath(), 0, 0).Read(id)
+	if err != nil {
+		return err
+	}
+	if len(lines) == 0 {
+		fmt.Println("This execution produced no output.")
+		return nil
+	}
+
+	streamStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#888888"))
+	for _, line := range lines {
+		fmt.Printf("%s %s %s\n", line.Time.Format("15:04:05.000"), streamStyle.Render(fmt.Sprintf("%-6s", line.Stream)), line.Text)
 	}
 	return nil
 }
@@ -517,4 +691,4 @@ func SelfUpdate() error {
 type Action struct {
 	Name         string
 	LastModified time.Time
-}
\ No newline at end of file
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"goction/internal/stats"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ShowHistory displays the execution history of one or all goctions
+func ShowHistory(args []string, statsManager *stats.Manager) error {
+	var query stats.HistoryQuery
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		query.Goction = args[0]
+		args = args[1:]
+	}
+
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	since := fs.String("since", "", "only show executions after this time (RFC 3339, YYYY-MM-DD or a duration such as 24h or 7d)")
+	until := fs.String("until", "", "only show executions before this time")
+	status := fs.String("status", "", "comma-separated list of statuses to show (success, failure, timeout)")
+	search := fs.String("search", "", "only show executions whose result or error contains this text")
+	fs.IntVar(&query.Limit, "limit", 20, "maximum number of executions to show")
+	fs.StringVar(&query.Cursor, "cursor", "", "cursor returned by a previous page")
+	fs.StringVar(&query.Order, "order", "desc", "sort order: asc or desc")
+	asJSON := fs.Bool("json", false, "print the result as JSON")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: goction history [goction-name] [flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var err error
+	if query.Since, err = stats.ParseTime(*since, now); err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	if query.Until, err = stats.ParseTime(*until, now); err != nil {
+		return fmt.Errorf("invalid --until: %w", err)
+	}
+	query.Statuses = stats.ParseStatuses(*status)
+	query.Search = *search
+
+	page, err := statsManager.QueryHistory(query)
+	if err != nil {
+		return fmt.Errorf("failed to query history: %w", err)
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(page)
+	}
+
+	printHistoryPage(page)
+	return nil
+}
+
+func printHistoryPage(page stats.HistoryPage) {
+	if len(page.Records) == 0 {
+		fmt.Println("No executions found.")
+		return
+	}
+
+	successStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#04B575"))
+	failureStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#FF4672"))
+	nameStyle := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(lipgloss.Color("#7D56F4"))
+
+	for _, record := range page.Records {
+		status := successStyle.Render(record.Status)
+		output := record.Result
+		if record.Status != stats.StatusSuccess {
+			status = failureStyle.Render(record.Status)
+			output = record.Error
+		}
+		fmt.Printf("%s  %s  %s  %-8s %10s  %s\n",
+			record.ID,
+			record.Timestamp.Format("2006-01-02 15:04:05"),
+			nameStyle.Render(record.Goction),
+			status,
+			record.Duration.Round(time.Microsecond),
+			truncate(output, 80))
+	}
+
+	fmt.Printf("\nShowing %d of %d executions.\n", len(page.Records), page.Total)
+	if page.NextCursor != "" {
+		fmt.Printf("Next page: --cursor %s\n", page.NextCursor)
+	}
+}
+
+func truncate(s string, max int) string {
+	s = strings.ReplaceAll(s, "\n", " ")
+	if len(s) <= max {
+		return s
+	}
+	return s[:max-3] + "..."
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"archive/zip"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -9,6 +10,7 @@ import (
 	"strings"
 
 	"goction/internal/config"
+	"goction/internal/goctions"
 )
 
 // ExportGoction exports a goction to a zip file
@@ -99,42 +101,12 @@ func ImportGoction(args []string, cfg *config.Config) error {
 	defer reader.Close()
 
 	goctionName := strings.TrimSuffix(filepath.Base(zipPath), ".zip")
-	destPath := filepath.Join(cfg.GoctionsDir, goctionName)
-
-	if _, err := os.Stat(destPath); !os.IsNotExist(err) {
+	err = goctions.Import(cfg.GoctionsDir, goctionName, &reader.Reader)
+	if errors.Is(err, goctions.ErrExists) {
 		return fmt.Errorf("goction '%s' already exists", goctionName)
 	}
-
-	for _, file := range reader.File {
-		filePath := filepath.Join(destPath, file.Name)
-
-		if file.FileInfo().IsDir() {
-			os.MkdirAll(filePath, file.Mode())
-			continue
-		}
-
-		if err := os.MkdirAll(filepath.Dir(filePath), os.ModePerm); err != nil {
-			return fmt.Errorf("failed to create directory: %w", err)
-		}
-
-		dstFile, err := os.OpenFile(filePath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, file.Mode())
-		if err != nil {
-			return fmt.Errorf("failed to create file: %w", err)
-		}
-
-		srcFile, err := file.Open()
-		if err != nil {
-			dstFile.Close()
-			return fmt.Errorf("failed to open file in zip: %w", err)
-		}
-
-		_, err = io.Copy(dstFile, srcFile)
-		dstFile.Close()
-		srcFile.Close()
-
-		if err != nil {
-			return fmt.Errorf("failed to write file: %w", err)
-		}
+	if err != nil {
+		return err
 	}
 
 	fmt.Printf("Goction '%s' imported successfully\n", goctionName)
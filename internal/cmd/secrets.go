@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"goction/internal/config"
+	"goction/internal/secrets"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+const secretUsage = "usage: goction secret [set <name> [value]|get <name>|list|rm <name>]"
+
+// ManageSecrets dispatches the secret subcommands
+func ManageSecrets(args []string, cfg *config.Config) error {
+	if len(args) == 0 {
+		return fmt.Errorf(secretUsage)
+	}
+
+	switch args[0] {
+	case "set":
+		return setSecret(args[1:], cfg)
+	case "get":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: goction secret get <name>")
+		}
+		store, err := openSecrets(cfg, false)
+		if err != nil {
+			return err
+		}
+		value, err := store.Get(args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Println(value)
+		return nil
+	case "list":
+		return listSecrets(cfg)
+	case "rm":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: goction secret rm <name>")
+		}
+		store, err := openSecrets(cfg, false)
+		if err != nil {
+			return err
+		}
+		if err := store.Delete(args[1]); err != nil {
+			return err
+		}
+		fmt.Printf("Secret '%s' removed\n", args[1])
+		return nil
+	default:
+		return fmt.Errorf("unknown secret subcommand: %s\n%s", args[0], secretUsage)
+	}
+}
+
+// setSecret stores a secret given as argument or, to keep it out of the shell history, read from stdin
+func setSecret(args []string, cfg *config.Config) error {
+	if len(args) < 1 || len(args) > 2 {
+		return fmt.Errorf("usage: goction secret set <name> [value]")
+	}
+	name := args[0]
+	if err := secrets.ValidateName(name); err != nil {
+		return err
+	}
+
+	var value string
+	if len(args) == 2 {
+		value = args[1]
+	} else {
+		fmt.Fprintf(os.Stderr, "Value for secret '%s': ", name)
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return fmt.Errorf("failed to read secret value: %w", err)
+		}
+		value = strings.TrimRight(line, "\r\n")
+	}
+
+	store, err := openSecrets(cfg, true)
+	if err != nil {
+		return err
+	}
+	if err := store.Set(name, value); err != nil {
+		return err
+	}
+	fmt.Printf("Secret '%s' saved\n", name)
+	return nil
+}
+
+func listSecrets(cfg *config.Config) error {
+	store, err := openSecrets(cfg, false)
+	if err != nil {
+		return err
+	}
+	infos, err := store.List()
+	if err != nil {
+		return err
+	}
+	if len(infos) == 0 {
+		fmt.Println("No secrets stored.")
+		return nil
+	}
+
+	nameStyle := lipgloss.NewStyle().Bold(true)
+	for _, info := range infos {
+		fmt.Printf("%-30s updated %s\n", nameStyle.Render(info.Name), info.UpdatedAt.Format("2006-01-02 15:04:05"))
+	}
+	return nil
+}
+
+// openSecrets opens the secrets store. When create is set, a master key file is generated if there is no key yet.
+func openSecrets(cfg *config.Config, create bool) (*secrets.Store, error) {
+	var key []byte
+	var err error
+	if create {
+		var created bool
+		key, created, err = secrets.LoadOrCreateMasterKey(cfg.MasterKeyPath())
+		if created {
+			fmt.Printf("Generated a new master key in %s\n", cfg.MasterKeyPath())
+		}
+	} else {
+		key, err = secrets.LoadMasterKey(cfg.MasterKeyPath())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return secrets.Open(cfg.SecretsPath(), key)
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"goction/internal/config"
+	"goction/internal/stats"
+)
+
+// ExportStats exports execution history or per-goction statistics to a file or stdout
+func ExportStats(args []string, statsManager *stats.Manager) error {
+	fs := flag.NewFlagSet("stats export", flag.ContinueOnError)
+	format := fs.String("format", stats.FormatCSV, "export format: "+strings.Join(stats.ExportFormats, ", "))
+	kind := fs.String("kind", stats.KindHistory, "what to export: history (one row per execution) or stats (one row per goction)")
+	goction := fs.String("goction", "", "only export this goction")
+	since := fs.String("since", "", "only export executions after this time (RFC 3339, YYYY-MM-DD or a duration such as 24h or 7d)")
+	until := fs.String("until", "", "only export executions before this time")
+	status := fs.String("status", "", "comma-separated list of statuses to export")
+	output := fs.String("output", "", "output file (default: stdout)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: goction stats export [flags]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	query := stats.HistoryQuery{
+		Goction:  *goction,
+		Statuses: stats.ParseStatuses(*status),
+	}
+	now := time.Now()
+	var err error
+	if query.Since, err = stats.ParseTime(*since, now); err != nil {
+		return fmt.Errorf("invalid --since: %w", err)
+	}
+	if query.Until, err = stats.ParseTime(*until, now); err != nil {
+		return fmt.Errorf("invalid --until: %w", err)
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	buffered := bufio.NewWriter(w)
+	if err := statsManager.Export(buffered, *format, *kind, query); err != nil {
+		return fmt.Errorf("failed to export stats: %w", err)
+	}
+	if err := buffered.Flush(); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+
+	if *output != "" {
+		fmt.Printf("Stats exported to %s\n", *output)
+	}
+	return nil
+}
+
+// ResetStats deletes the statistics of one goction, of every goction, or of goctions that no longer exist
+func ResetStats(args []string, cfg *config.Config, statsManager *stats.Manager) error {
+	var name string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name = args[0]
+		args = args[1:]
+	}
+
+	fs := flag.NewFlagSet("stats reset", flag.ContinueOnError)
+	orphans := fs.Bool("orphans", false, "only delete statistics of goctions that no longer exist")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: goction stats reset [goction-name] [--orphans]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *orphans {
+		removed, err := statsManager.Prune(func(name string) bool {
+			return goctionExists(cfg, name)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to delete orphaned statistics: %w", err)
+		}
+		if len(removed) == 0 {
+			fmt.Println("No orphaned statistics found.")
+			return nil
+		}
+		fmt.Printf("Deleted statistics of removed goctions: %s\n", strings.Join(removed, ", "))
+		return nil
+	}
+
+	if name == "" {
+		if err := statsManager.Reset(); err != nil {
+			return fmt.Errorf("failed to reset statistics: %w", err)
+		}
+		fmt.Println("All statistics have been reset.")
+		return nil
+	}
+
+	if _, ok := statsManager.GetStats(name); !ok {
+		return fmt.Errorf("no statistics available for goction '%s'", name)
+	}
+	if err := statsManager.Reset(name); err != nil {
+		return fmt.Errorf("failed to reset statistics: %w", err)
+	}
+	fmt.Printf("Statistics of goction '%s' have been reset.\n", name)
+	return nil
+}
+
+// ImportStats merges execution history exported by 'goction stats export' or copied from a stats file
+func ImportStats(args []string, statsManager *stats.Manager) error {
+	fs := flag.NewFlagSet("stats import", flag.ContinueOnError)
+	format := fs.String("format", "", "input format: csv, jsonl, columnar or stats (default: detected from the file)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: goction stats import <file> [--format csv|jsonl|columnar|stats]")
+		fs.PrintDefaults()
+	}
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fs.Usage()
+		return fmt.Errorf("missing file to import")
+	}
+	path := args[0]
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *format == "" {
+		*format = stats.DetectFormat(path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open import file: %w", err)
+	}
+	defer file.Close()
+
+	records, err := stats.ReadRecords(bufio.NewReader(file), *format)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	imported, skipped, err := statsManager.Import(records)
+	if err != nil {
+		return fmt.Errorf("failed to import statistics: %w", err)
+	}
+
+	fmt.Printf("Imported %d executions (%d already present).\n", imported, skipped)
+	return nil
+}
+
+func goctionExists(cfg *config.Config, name string) bool {
+	info, err := os.Stat(filepath.Join(cfg.GoctionsDir, name))
+	return err == nil && info.IsDir()
+}
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -12,38 +14,256 @@ import (
 const GoctionVersion = "1.0.0"
 const ConfigDir = "/etc/goction"
 
+// Execution modes
+const (
+	// ExecutionModeProcess runs each execution in a child process with its own environment and working directory
+	ExecutionModeProcess = "process"
+	// ExecutionModePlugin runs goctions inside the goction process. It is the mode of configurations that set none.
+	ExecutionModePlugin = "plugin"
+)
+
 // Config holds the application configuration
 type Config struct {
-	GoctionsDir       string `json:"goctions_dir"`
-	Port              int    `json:"port"`
-	LogFile           string `json:"log_file"`
-	APIToken          string `json:"api_token"`
-	StatsFile         string `json:"stats_file"`
-	DashboardUsername string `json:"dashboard_username"`
-	DashboardPassword string `json:"dashboard_password"`
+	GoctionsDir       string            `json:"goctions_dir"`
+	Port              int               `json:"port"`
+	LogFile           string            `json:"log_file"`
+	Log               LogConfig         `json:"log"`
+	APIToken          string            `json:"api_token"`
+	APIKeys           map[string]string `json:"api_keys,omitempty"`
+	StatsFile         string            `json:"stats_file"`
+	DashboardUsername string            `json:"dashboard_username"`
+	DashboardPassword string            `json:"dashboard_password"`
+	SessionKey        string            `json:"session_key"`
+	MetricsToken      string            `json:"metrics_token"`
+	ExecutionTimeout  int               `json:"execution_timeout"`
+	Alerts            AlertsConfig      `json:"alerts"`
+
+	Notifications       map[string][]NotificationHook `json:"notifications,omitempty"`
+	NotificationLogFile string                        `json:"notification_log_file,omitempty"`
+
+	SecretsFile   string `json:"secrets_file,omitempty"`
+	MasterKeyFile string `json:"master_key_file,omitempty"`
+
+	ExecutionMode string `json:"execution_mode,omitempty"`
+	WorkDir       string `json:"work_dir,omitempty"`
+	Limits        Limits `json:"limits"`
+
+	ExecutionLogs ExecutionLogsConfig `json:"execution_logs"`
+
+	// path is the file the configuration was loaded from, options how it was loaded,
+	// sources where each value came from and unknownKeys the problems found by findUnknownKeys in that file
+	path        string
+	options     LoadOptions
+	sources     map[string]string
+	unknownKeys []string
 }
 
-// Load reads the configuration file and returns a Config struct
-func Load() (*Config, error) {
-	configPath := filepath.Join(ConfigDir, "config.json")
+// Log formats
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
 
-	file, err := os.Open(configPath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open config file: %w", err)
+// Log outputs
+const (
+	LogOutputFile     = "file"
+	LogOutputStdout   = "stdout"
+	LogOutputStderr   = "stderr"
+	LogOutputSyslog   = "syslog"
+	LogOutputJournald = "journald"
+)
+
+// LogConfig configures the logger shared by the commands and the server
+type LogConfig struct {
+	// Level is one of trace, debug, info, warn, error
+	Level string `json:"level,omitempty"`
+	// Format is text or json
+	Format string `json:"format,omitempty"`
+	// Outputs lists where log lines are written: file (log_file), stdout, stderr, syslog or journald
+	Outputs []string `json:"outputs,omitempty"`
+	// Rotation applies to the file output
+	Rotation LogRotation `json:"rotation"`
+}
+
+// Log rotation intervals
+const (
+	LogRotateHourly = "hourly"
+	LogRotateDaily  = "daily"
+)
+
+// LogRotation rotates the log file when it exceeds MaxSizeMB or, with an Interval, at the start of each hour or day.
+// Rotated files are kept next to the log file, compressed if Compress is set, and removed beyond MaxFiles
+// or after MaxAgeDays. Zero disables a limit.
+type LogRotation struct {
+	MaxSizeMB  int    `json:"max_size_mb"`
+	Interval   string `json:"interval,omitempty"`
+	MaxFiles   int    `json:"max_files"`
+	MaxAgeDays int    `json:"max_age_days"`
+	Compress   bool   `json:"compress"`
+}
+
+// NotificationHook sends a JSON description of an execution to URL when one of the On events occurs:
+// "success", "failure" (including timeouts) or "duration" (the execution took at least DurationThreshold).
+// When Secret is set, the body is signed with HMAC-SHA256.
+type NotificationHook struct {
+	URL               string            `json:"url"`
+	On                []string          `json:"on"`
+	DurationThreshold string            `json:"duration_threshold,omitempty"`
+	Secret            string            `json:"secret,omitempty"`
+	MaxRetries        int               `json:"max_retries,omitempty"`
+	Headers           map[string]string `json:"headers,omitempty"`
+}
+
+// NotificationLogPath returns the file notification deliveries are logged to
+func (c *Config) NotificationLogPath() string {
+	if c.NotificationLogFile != "" {
+		return c.NotificationLogFile
 	}
-	defer file.Close()
+	return filepath.Join(filepath.Dir(c.StatsFile), "goction_notifications.jsonl")
+}
 
-	var cfg Config
-	if err := json.NewDecoder(file).Decode(&cfg); err != nil {
-		return nil, fmt.Errorf("failed to decode config file: %w", err)
+// SecretsPath returns the encrypted secrets store, by default secrets.json next to the configuration file
+func (c *Config) SecretsPath() string {
+	if c.SecretsFile != "" {
+		return c.SecretsFile
 	}
+	return filepath.Join(filepath.Dir(c.Path()), "secrets.json")
+}
 
-	return &cfg, nil
+// MasterKeyPath returns the file holding the secrets master key, by default master.key next to the configuration file
+func (c *Config) MasterKeyPath() string {
+	if c.MasterKeyFile != "" {
+		return c.MasterKeyFile
+	}
+	return filepath.Join(filepath.Dir(c.Path()), "master.key")
+}
+
+// PluginMode reports whether goctions run inside the goction process: execution_mode is plugin or unset
+func (c *Config) PluginMode() bool {
+	return c.ExecutionMode != ExecutionModeProcess
+}
+
+// WorkPath returns the directory holding the working directory of each goction,
+// by default work next to the stats file
+func (c *Config) WorkPath() string {
+	if c.WorkDir != "" {
+		return c.WorkDir
+	}
+	return filepath.Join(filepath.Dir(c.StatsFile), "work")
+}
+
+// ExecutionLogsConfig configures where the output of each execution is stored and for how long
+type ExecutionLogsConfig struct {
+	// Dir defaults to execution_logs next to the stats file
+	Dir string `json:"dir,omitempty"`
+	// MaxSizeKB truncates the output of an execution, 0 for no limit
+	MaxSizeKB int `json:"max_size_kb"`
+	// RetentionDays removes the output of older executions, 0 to keep it
+	RetentionDays int `json:"retention_days"`
 }
 
-// Save writes the configuration to the config file
+// ExecutionLogsPath returns the directory holding the output of executions
+func (c *Config) ExecutionLogsPath() string {
+	if c.ExecutionLogs.Dir != "" {
+		return c.ExecutionLogs.Dir
+	}
+	return filepath.Join(filepath.Dir(c.StatsFile), "execution_logs")
+}
+
+// Limits restricts the resources of a goction process. Zero means unlimited.
+// The configuration sets defaults that goction manifests override field by field.
+type Limits struct {
+	CPUSeconds int `json:"cpu_seconds,omitempty"`
+	MemoryMB   int `json:"memory_mb,omitempty"`
+	OpenFiles  int `json:"open_files,omitempty"`
+	Processes  int `json:"processes,omitempty"`
+}
+
+// Merge returns l with the non-zero fields of override
+func (l Limits) Merge(override Limits) Limits {
+	if override.CPUSeconds != 0 {
+		l.CPUSeconds = override.CPUSeconds
+	}
+	if override.MemoryMB != 0 {
+		l.MemoryMB = override.MemoryMB
+	}
+	if override.OpenFiles != 0 {
+		l.OpenFiles = override.OpenFiles
+	}
+	if override.Processes != 0 {
+		l.Processes = override.Processes
+	}
+	return l
+}
+
+// Sandbox isolates a goction process with Linux namespaces and seccomp.
+// Filesystem and network options imply Namespaces.
+type Sandbox struct {
+	// Namespaces runs the goction in new mount, PID, IPC and UTS namespaces,
+	// and in a new user namespace when goction does not run as root. Every other option implies it.
+	Namespaces bool `json:"namespaces,omitempty"`
+	// IsolateNetwork runs the goction in a new network namespace without connectivity
+	IsolateNetwork bool `json:"isolate_network,omitempty"`
+	// Seccomp denies system calls used to escape or administer the host (mount, ptrace, module loading, ...).
+	// The filter is installed for every sandboxed goction.
+	Seccomp bool `json:"seccomp,omitempty"`
+	// ReadOnly makes the whole filesystem read-only, except the goction working directory
+	ReadOnly bool `json:"read_only,omitempty"`
+	// ReadOnlyPaths are made read-only
+	ReadOnlyPaths []string `json:"read_only_paths,omitempty"`
+	// HidePaths are replaced by an empty file or directory
+	HidePaths []string `json:"hide_paths,omitempty"`
+}
+
+// Enabled reports whether any isolation is requested
+func (s Sandbox) Enabled() bool {
+	return s.Namespaces || s.IsolateNetwork || s.Seccomp || s.ReadOnly || len(s.ReadOnlyPaths) > 0 || len(s.HidePaths) > 0
+}
+
+// AlertsConfig configures the alerting rules evaluated by the server and where alerts are sent
+type AlertsConfig struct {
+	EvaluationInterval string         `json:"evaluation_interval,omitempty"`
+	Rules              []AlertRule    `json:"rules,omitempty"`
+	Channels           []AlertChannel `json:"channels,omitempty"`
+	SMTP               SMTPConfig     `json:"smtp"`
+}
+
+// AlertRule is a condition on goction statistics.
+// Type is "error_rate" (threshold in percent), "latency" (threshold in seconds for the given percentile)
+// or "no_success" (no successful execution during the window).
+type AlertRule struct {
+	Name       string   `json:"name"`
+	Goction    string   `json:"goction,omitempty"`
+	Type       string   `json:"type"`
+	Threshold  float64  `json:"threshold,omitempty"`
+	Percentile float64  `json:"percentile,omitempty"`
+	Window     string   `json:"window"`
+	For        string   `json:"for,omitempty"`
+	MinCalls   int      `json:"min_calls,omitempty"`
+	Channels   []string `json:"channels,omitempty"`
+}
+
+// AlertChannel is a notification target: a "webhook" URL, an "email" recipient list or a "goction" to run
+type AlertChannel struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	URL     string   `json:"url,omitempty"`
+	To      []string `json:"to,omitempty"`
+	Goction string   `json:"goction,omitempty"`
+}
+
+// SMTPConfig is the mail server used by email alert channels
+type SMTPConfig struct {
+	Host     string `json:"host,omitempty"`
+	Port     int    `json:"port,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	From     string `json:"from,omitempty"`
+}
+
+// Save writes the configuration to the file it was loaded from
 func (c *Config) Save() error {
-	configPath := filepath.Join(ConfigDir, "config.json")
+	configPath := c.Path()
 
 	file, err := os.Create(configPath)
 	if err != nil {
@@ -60,10 +280,8 @@ func (c *Config) Save() error {
 	return nil
 }
 
-// Reset resets the configuration to default values
-func Reset() error {
-	configPath := filepath.Join(ConfigDir, "config.json")
-
+// Reset replaces the configuration file at configPath with default values
+func Reset(configPath string) error {
 	// Remove the existing config file
 	if err := os.Remove(configPath); err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("failed to remove existing config file: %w", err)
@@ -96,6 +314,11 @@ func (c *Config) InitializeLogFile() error {
 }
 
 func createDefaultConfig(configPath string) (*Config, error) {
+	sessionKey, err := newSessionKey()
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := &Config{
 		GoctionsDir:       "/etc/goction/goctions",
 		Port:              8080,
@@ -104,6 +327,8 @@ func createDefaultConfig(configPath string) (*Config, error) {
 		StatsFile:         "/var/log/goction/goction_stats.json",
 		DashboardUsername: "admin",
 		DashboardPassword: uuid.New().String(),
+		SessionKey:        sessionKey,
+		path:              configPath,
 	}
 
 	if err := cfg.Save(); err != nil {
@@ -113,6 +338,15 @@ func createDefaultConfig(configPath string) (*Config, error) {
 	return cfg, nil
 }
 
+// newSessionKey returns 32 random bytes, hex encoded like the key generated by install.sh
+func newSessionKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", fmt.Errorf("failed to generate session key: %w", err)
+	}
+	return hex.EncodeToString(key), nil
+}
+
 func loadExistingConfig(configPath string) (*Config, error) {
 	file, err := os.Open(configPath)
 	if err != nil {
@@ -0,0 +1,337 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Sources of configuration values, in increasing order of precedence
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+)
+
+// PathEnv is the environment variable selecting the configuration file
+const PathEnv = "GOCTION_CONFIG"
+
+// EnvPrefix prefixes the environment variables overriding configuration values.
+// The variable of a key is the prefix followed by the upper-cased key, dots replaced by underscores:
+// GOCTION_PORT overrides "port" and GOCTION_ALERTS_SMTP_HOST overrides "alerts.smtp.host".
+const EnvPrefix = "GOCTION_"
+
+// LoadOptions selects the configuration file and the command line overrides
+type LoadOptions struct {
+	// Path of the configuration file. Empty selects $GOCTION_CONFIG, then DefaultPath.
+	Path string
+	// Overrides are values given on the command line, keyed by configuration key
+	Overrides map[string]string
+	// SkipValidation returns the configuration even if it is invalid, so that it can be inspected and fixed
+	SkipValidation bool
+}
+
+// Field is a configuration value and where it came from
+type Field struct {
+	Key    string
+	Value  string
+	Source string
+}
+
+// DefaultPath returns the configuration file used when neither --config nor GOCTION_CONFIG is set
+func DefaultPath() string {
+	return filepath.Join(ConfigDir, "config.json")
+}
+
+// ResolvePath returns the configuration file path: path if set, then $GOCTION_CONFIG, then DefaultPath
+func ResolvePath(path string) string {
+	if path != "" {
+		return path
+	}
+	if path := os.Getenv(PathEnv); path != "" {
+		return path
+	}
+	return DefaultPath()
+}
+
+// defaults returns the values used for keys set neither in the file, the environment nor on the command line
+func defaults() *Config {
+	return &Config{
+		GoctionsDir: filepath.Join(ConfigDir, "goctions"),
+		Port:        8080,
+		LogFile:     "/var/log/goction/goction.log",
+		Log: LogConfig{
+			Level:   "info",
+			Format:  LogFormatText,
+			Outputs: []string{LogOutputFile},
+			Rotation: LogRotation{
+				MaxSizeMB: 100,
+				MaxFiles:  5,
+				Compress:  true,
+			},
+		},
+		StatsFile:         "/var/log/goction/goction_stats.json",
+		DashboardUsername: "admin",
+		ExecutionLogs: ExecutionLogsConfig{
+			MaxSizeKB:     1024,
+			RetentionDays: 30,
+		},
+	}
+}
+
+// Load builds the configuration from, in increasing order of precedence:
+// built-in defaults, the configuration file, GOCTION_* environment variables and command line overrides
+func Load(opts LoadOptions) (*Config, error) {
+	path := ResolvePath(opts.Path)
+
+	cfg, doc, err := readFile(path, defaults())
+	if err != nil {
+		return nil, err
+	}
+
+	cfg.options = opts
+	cfg.sources = make(map[string]string)
+	for _, f := range cfg.fields() {
+		cfg.sources[f.key] = SourceDefault
+		if lookup(doc, f.key) {
+			cfg.sources[f.key] = SourceFile
+		}
+	}
+
+	for _, f := range cfg.fields() {
+		name := EnvName(f.key)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := setValue(f.value, value); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		cfg.sources[f.key] = SourceEnv
+	}
+
+	for key, value := range opts.Overrides {
+		if err := cfg.Set(key, value); err != nil {
+			return nil, err
+		}
+		cfg.sources[key] = SourceFlag
+	}
+
+	if !opts.SkipValidation {
+		if err := cfg.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return cfg, nil
+}
+
+// LoadFile reads the configuration file over the built-in defaults, without environment or command line overrides.
+// Use it to modify and Save the file without persisting overridden values.
+func LoadFile(path string) (*Config, error) {
+	cfg, _, err := readFile(path, defaults())
+	return cfg, err
+}
+
+// readFile decodes the file at path over cfg and also returns it as a generic document
+func readFile(path string, cfg *Config) (*Config, map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open config file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode config file %s: %w", path, err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode config file %s: %w", path, err)
+	}
+
+	cfg.path = path
+	cfg.unknownKeys = findUnknownKeys(doc)
+	return cfg, doc, nil
+}
+
+// lookup reports whether the dotted key is set in a decoded JSON document
+func lookup(doc map[string]any, key string) bool {
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		value, ok := doc[part]
+		if !ok {
+			return false
+		}
+		if i == len(parts)-1 {
+			return true
+		}
+		if doc, ok = value.(map[string]any); !ok {
+			return false
+		}
+	}
+	return false
+}
+
+// Path returns the file the configuration was loaded from and is saved to
+func (c *Config) Path() string {
+	if c.path == "" {
+		return DefaultPath()
+	}
+	return c.path
+}
+
+// Source returns where the value of key came from: SourceDefault, SourceFile, SourceEnv or SourceFlag
+func (c *Config) Source(key string) string {
+	if source, ok := c.sources[key]; ok {
+		return source
+	}
+	return SourceDefault
+}
+
+// Fields returns every configuration value with its source, in declaration order
+func (c *Config) Fields() []Field {
+	var fields []Field
+	for _, f := range c.fields() {
+		fields = append(fields, Field{Key: f.key, Value: formatValue(f.value), Source: c.Source(f.key)})
+	}
+	return fields
+}
+
+// Get returns the value of key, formatted as accepted by Set
+func (c *Config) Get(key string) (string, error) {
+	f, ok := c.field(key)
+	if !ok {
+		return "", c.unknownKeyError(key)
+	}
+	return formatValue(f.value), nil
+}
+
+// Set parses value according to the type of key and stores it.
+// Maps and lists are given as JSON.
+func (c *Config) Set(key, value string) error {
+	f, ok := c.field(key)
+	if !ok {
+		return c.unknownKeyError(key)
+	}
+	if err := setValue(f.value, value); err != nil {
+		return fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+	return nil
+}
+
+// Keys returns every configuration key in declaration order
+func Keys() []string {
+	var keys []string
+	for _, f := range (&Config{}).fields() {
+		keys = append(keys, f.key)
+	}
+	return keys
+}
+
+func (c *Config) unknownKeyError(key string) error {
+	for _, known := range Keys() {
+		if strings.HasPrefix(known, key+".") {
+			return fmt.Errorf("%q is a section, use one of its keys such as %q", key, known)
+		}
+	}
+	if problems := findUnknownKeys(map[string]any{key: nil}); len(problems) > 0 {
+		return fmt.Errorf("%s", problems[0])
+	}
+	return fmt.Errorf("unknown configuration key %q", key)
+}
+
+// EnvName returns the environment variable overriding key
+func EnvName(key string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// field is a settable configuration value identified by its dotted JSON key
+type field struct {
+	key   string
+	value reflect.Value
+}
+
+func (c *Config) fields() []field {
+	return collectFields(reflect.ValueOf(c).Elem(), "")
+}
+
+func (c *Config) field(key string) (field, bool) {
+	for _, f := range c.fields() {
+		if f.key == key {
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+// collectFields lists the JSON fields of v, descending into nested structs.
+// Maps and slices are single values encoded as JSON.
+func collectFields(v reflect.Value, prefix string) []field {
+	var fields []field
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name := strings.Split(sf.Tag.Get("json"), ",")[0]
+		if !sf.IsExported() || name == "" || name == "-" {
+			continue
+		}
+		key := prefix + name
+		if sf.Type.Kind() == reflect.Struct {
+			fields = append(fields, collectFields(v.Field(i), key+".")...)
+			continue
+		}
+		fields = append(fields, field{key: key, value: v.Field(i)})
+	}
+	return fields
+}
+
+func setValue(v reflect.Value, raw string) error {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", raw)
+		}
+		v.SetInt(n)
+	case reflect.Float64:
+		n, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", raw)
+		}
+		v.SetFloat(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", raw)
+		}
+		v.SetBool(b)
+	default:
+		target := reflect.New(v.Type())
+		if err := json.Unmarshal([]byte(raw), target.Interface()); err != nil {
+			return fmt.Errorf("expected a JSON %s: %w", v.Kind(), err)
+		}
+		v.Set(target.Elem())
+	}
+	return nil
+}
+
+func formatValue(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return ""
+		}
+	}
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return fmt.Sprint(v.Interface())
+	}
+	return string(data)
+}
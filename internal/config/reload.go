@@ -0,0 +1,84 @@
+package config
+
+import "strings"
+
+// RestartKeys are the keys whose changes only take effect when the server restarts
+var RestartKeys = []string{
+	"port", "goctions_dir", "log_file", "log.outputs", "stats_file", "notification_log_file", "session_key",
+	"log.rotation.max_size_mb", "log.rotation.interval", "log.rotation.max_files", "log.rotation.max_age_days", "log.rotation.compress",
+}
+
+// Change is a configuration value that differs between two configurations
+type Change struct {
+	Key     string `json:"key"`
+	Old     string `json:"old"`
+	New     string `json:"new"`
+	Restart bool   `json:"restart,omitempty"`
+}
+
+// Reload loads the configuration again from the same file, environment and command line overrides
+func (c *Config) Reload() (*Config, error) {
+	opts := c.options
+	opts.Path = c.Path()
+	return Load(opts)
+}
+
+// Diff returns the values that differ from c in next.
+// Values of secret keys are masked so that the changes can be logged.
+func (c *Config) Diff(next *Config) []Change {
+	nextFields := make(map[string]string)
+	for _, f := range next.Fields() {
+		nextFields[f.Key] = f.Value
+	}
+
+	var changes []Change
+	for _, f := range c.Fields() {
+		value := nextFields[f.Key]
+		if value == f.Value {
+			continue
+		}
+		change := Change{Key: f.Key, Old: f.Value, New: value, Restart: requiresRestart(f.Key)}
+		if IsSecretKey(f.Key) {
+			change.Old, change.New = MaskSecret(change.Old), MaskSecret(change.New)
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// KeepRestartValues copies the values of RestartKeys from c into next,
+// so that next can be applied to a running server
+func (c *Config) KeepRestartValues(next *Config) {
+	for _, key := range RestartKeys {
+		if value, err := c.Get(key); err == nil {
+			next.Set(key, value)
+		}
+	}
+}
+
+// IsSecretKey reports whether the value of key is a credential that must not be displayed or logged
+func IsSecretKey(key string) bool {
+	for _, word := range []string{"token", "password", "api_keys", "secret", "session_key", "notifications"} {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+	return false
+}
+
+func requiresRestart(key string) bool {
+	for _, restartKey := range RestartKeys {
+		if key == restartKey {
+			return true
+		}
+	}
+	return false
+}
+
+// MaskSecret hides the value of a secret key, keeping only whether it is set
+func MaskSecret(value string) string {
+	if value == "" {
+		return ""
+	}
+	return "********"
+}
@@ -0,0 +1,272 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ValidationError lists every problem found in a configuration
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate checks the configuration values and reports unknown keys found in the configuration file.
+// It returns a *ValidationError listing every problem.
+func (c *Config) Validate() error {
+	return validationError(c.problems())
+}
+
+// MinSessionKeyLength is the minimum length of the key signing dashboard sessions
+const MinSessionKeyLength = 32
+
+// ValidateServer runs the checks of Validate plus those only the server needs: a non-empty api_token,
+// a session_key and writable paths. The other commands may run as a user that cannot write to the service paths.
+func (c *Config) ValidateServer() error {
+	problems := c.problems()
+	if strings.TrimSpace(c.APIToken) == "" {
+		problems = append(problems, "api_token: must not be empty")
+	}
+	if len(c.SessionKey) < MinSessionKeyLength {
+		problems = append(problems, fmt.Sprintf("session_key: must be at least %d characters long, e.g. the output of 'openssl rand -hex 32'", MinSessionKeyLength))
+	}
+	for _, p := range c.paths() {
+		if p.path == "" {
+			continue
+		}
+		dir := p.path
+		if !p.isDir {
+			dir = filepath.Dir(p.path)
+		}
+		if err := checkWritable(dir); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", p.key, err))
+		}
+	}
+	return validationError(problems)
+}
+
+func validationError(problems []string) error {
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+type configPath struct {
+	key, path string
+	isDir     bool
+}
+
+// paths returns the files and directories written by the server
+func (c *Config) paths() []configPath {
+	return []configPath{
+		{"goctions_dir", c.GoctionsDir, true},
+		{"log_file", c.LogFile, false},
+		{"stats_file", c.StatsFile, false},
+		{"notification_log_file", c.NotificationLogPath(), false},
+		{"work_dir", c.WorkPath(), true},
+		{"execution_logs.dir", c.ExecutionLogsPath(), true},
+	}
+}
+
+func (c *Config) problems() []string {
+	problems := append([]string(nil), c.unknownKeys...)
+
+	if c.Port < 1 || c.Port > 65535 {
+		problems = append(problems, fmt.Sprintf("port: %d is not between 1 and 65535", c.Port))
+	}
+	if c.ExecutionTimeout < 0 {
+		problems = append(problems, fmt.Sprintf("execution_timeout: %d is negative", c.ExecutionTimeout))
+	}
+	switch c.ExecutionMode {
+	case "", ExecutionModeProcess, ExecutionModePlugin:
+	default:
+		problems = append(problems, fmt.Sprintf("execution_mode: unknown mode %q (expected %s or %s)", c.ExecutionMode, ExecutionModeProcess, ExecutionModePlugin))
+	}
+	problems = append(problems, c.Log.validate()...)
+	counts := []struct {
+		key   string
+		value int
+	}{
+		{"limits.cpu_seconds", c.Limits.CPUSeconds},
+		{"limits.memory_mb", c.Limits.MemoryMB},
+		{"limits.open_files", c.Limits.OpenFiles},
+		{"limits.processes", c.Limits.Processes},
+		{"execution_logs.max_size_kb", c.ExecutionLogs.MaxSizeKB},
+		{"execution_logs.retention_days", c.ExecutionLogs.RetentionDays},
+	}
+	for _, count := range counts {
+		if count.value < 0 {
+			problems = append(problems, fmt.Sprintf("%s: %d is negative", count.key, count.value))
+		}
+	}
+	for name, token := range c.APIKeys {
+		if strings.TrimSpace(token) == "" {
+			problems = append(problems, fmt.Sprintf("api_keys: key %q has an empty token", name))
+		}
+	}
+	for _, p := range c.paths() {
+		if p.path == "" {
+			problems = append(problems, fmt.Sprintf("%s: must be set", p.key))
+		}
+	}
+	return problems
+}
+
+func (l LogConfig) validate() []string {
+	var problems []string
+	if _, err := logrus.ParseLevel(l.Level); err != nil {
+		problems = append(problems, fmt.Sprintf("log.level: unknown level %q (expected trace, debug, info, warn or error)", l.Level))
+	}
+	switch l.Format {
+	case LogFormatText, LogFormatJSON:
+	default:
+		problems = append(problems, fmt.Sprintf("log.format: unknown format %q (expected %s or %s)", l.Format, LogFormatText, LogFormatJSON))
+	}
+	if len(l.Outputs) == 0 {
+		problems = append(problems, "log.outputs: must list at least one output")
+	}
+	for _, output := range l.Outputs {
+		switch output {
+		case LogOutputFile, LogOutputStdout, LogOutputStderr, LogOutputSyslog, LogOutputJournald:
+		default:
+			problems = append(problems, fmt.Sprintf("log.outputs: unknown output %q (expected file, stdout, stderr, syslog or journald)", output))
+		}
+	}
+	switch l.Rotation.Interval {
+	case "", LogRotateHourly, LogRotateDaily:
+	default:
+		problems = append(problems, fmt.Sprintf("log.rotation.interval: unknown interval %q (expected %s or %s)", l.Rotation.Interval, LogRotateHourly, LogRotateDaily))
+	}
+	rotation := []struct {
+		key   string
+		value int
+	}{
+		{"log.rotation.max_size_mb", l.Rotation.MaxSizeMB},
+		{"log.rotation.max_files", l.Rotation.MaxFiles},
+		{"log.rotation.max_age_days", l.Rotation.MaxAgeDays},
+	}
+	for _, r := range rotation {
+		if r.value < 0 {
+			problems = append(problems, fmt.Sprintf("%s: %d is negative", r.key, r.value))
+		}
+	}
+	return problems
+}
+
+// checkWritable checks that dir, or its closest existing parent if it does not exist yet, is a writable directory
+func checkWritable(dir string) error {
+	for {
+		info, err := os.Stat(dir)
+		if os.IsNotExist(err) {
+			parent := filepath.Dir(dir)
+			if parent == dir {
+				return fmt.Errorf("%s does not exist", dir)
+			}
+			dir = parent
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("%s is not a directory", dir)
+		}
+		break
+	}
+
+	file, err := os.CreateTemp(dir, ".goction-write-test-*")
+	if err != nil {
+		return fmt.Errorf("%s is not writable", dir)
+	}
+	file.Close()
+	os.Remove(file.Name())
+	return nil
+}
+
+// findUnknownKeys returns a problem for every key of the configuration file that is not a configuration key,
+// suggesting the closest known key
+func findUnknownKeys(doc map[string]any) []string {
+	known := make(map[string]bool)
+	sections := make(map[string]bool)
+	for _, f := range (&Config{}).fields() {
+		known[f.key] = true
+		parts := strings.Split(f.key, ".")
+		for i := 1; i < len(parts); i++ {
+			sections[strings.Join(parts[:i], ".")] = true
+		}
+	}
+
+	var problems []string
+	var walk func(doc map[string]any, prefix string)
+	walk = func(doc map[string]any, prefix string) {
+		keys := make([]string, 0, len(doc))
+		for key := range doc {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			full := prefix + key
+			if known[full] {
+				continue
+			}
+			if nested, ok := doc[key].(map[string]any); ok && sections[full] {
+				walk(nested, full+".")
+				continue
+			}
+
+			problem := fmt.Sprintf("unknown key %q", full)
+			if suggestion := closestKey(full, known, sections); suggestion != "" {
+				problem += fmt.Sprintf(" (did you mean %q?)", suggestion)
+			}
+			problems = append(problems, problem)
+		}
+	}
+	walk(doc, "")
+	return problems
+}
+
+// closestKey returns the known key or section nearest to key by edit distance, or "" if none is close
+func closestKey(key string, known, sections map[string]bool) string {
+	best, bestDistance := "", len(key)/3+1
+	if bestDistance < 2 {
+		bestDistance = 2
+	}
+	for _, candidates := range []map[string]bool{known, sections} {
+		for candidate := range candidates {
+			if d := levenshtein(key, candidate); d <= bestDistance && (best == "" || d < bestDistance || d == bestDistance && candidate < best) {
+				best, bestDistance = candidate, d
+			}
+		}
+	}
+	return best
+}
+
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
@@ -0,0 +1,243 @@
+// Package execlog stores the output of each goction execution, linked to its execution ID.
+//
+// Each execution has its own file in the store directory with one JSON line per output line.
+package execlog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Output streams
+const (
+	StreamStdout = "stdout"
+	StreamStderr = "stderr"
+	// StreamLog holds the lines logged through the logger handed to the goction
+	StreamLog = "log"
+)
+
+var (
+	// ErrNotFound is returned when no output was stored for an execution
+	ErrNotFound = errors.New("no logs found for this execution")
+	// ErrInvalidID is returned for execution IDs that are not UUIDs
+	ErrInvalidID = errors.New("invalid execution ID")
+)
+
+// Line is one line of output of an execution
+type Line struct {
+	Time   time.Time `json:"time"`
+	Stream string    `json:"stream"`
+	Text   string    `json:"text"`
+}
+
+// Store holds the output of executions in a directory
+type Store struct {
+	dir       string
+	maxSize   int64
+	retention time.Duration
+
+	mu         sync.Mutex
+	lastPruned time.Time
+}
+
+// Open returns the store in dir. The output of an execution is truncated beyond maxSize bytes (0 for no limit),
+// and outputs older than retention are removed (0 to keep them).
+func Open(dir string, maxSize int64, retention time.Duration) *Store {
+	return &Store{dir: dir, maxSize: maxSize, retention: retention}
+}
+
+// Create starts storing the output of an execution. Lines are passed through redact before being stored.
+func (s *Store) Create(executionID string, redact func(string) string) (*Writer, error) {
+	path, err := s.path(executionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(s.dir, 0750); err != nil {
+		return nil, fmt.Errorf("failed to create execution log directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0640)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create execution log: %w", err)
+	}
+
+	s.prune()
+	return &Writer{file: file, encoder: json.NewEncoder(file), maxSize: s.maxSize, redact: redact}, nil
+}
+
+// Read returns the stored output of an execution
+func (s *Store) Read(executionID string) ([]Line, error) {
+	path, err := s.path(executionID)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	lines := []Line{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxLineLength*2)
+	for scanner.Scan() {
+		var line Line
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// Remove deletes the output of an execution
+func (s *Store) Remove(executionID string) error {
+	path, err := s.path(executionID)
+	if err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// path returns the file of an execution, rejecting IDs that could escape the store directory
+func (s *Store) path(executionID string) (string, error) {
+	if executionID == "" || strings.Trim(executionID, "abcdefABCDEF0123456789-") != "" {
+		return "", fmt.Errorf("%w %q", ErrInvalidID, executionID)
+	}
+	return filepath.Join(s.dir, executionID+".jsonl"), nil
+}
+
+// prune removes the outputs older than the retention, at most once an hour
+func (s *Store) prune() {
+	if s.retention <= 0 {
+		return
+	}
+	s.mu.Lock()
+	if time.Since(s.lastPruned) < time.Hour {
+		s.mu.Unlock()
+		return
+	}
+	s.lastPruned = time.Now()
+	s.mu.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return
+	}
+	cutoff := time.Now().Add(-s.retention)
+	for _, entry := range entries {
+		if info, err := entry.Info(); err == nil && !entry.IsDir() && info.ModTime().Before(cutoff) {
+			os.Remove(filepath.Join(s.dir, entry.Name()))
+		}
+	}
+}
+
+// Discard returns a writer that does not store the output of an execution, only mirrors it
+func Discard(redact func(string) string) *Writer {
+	return &Writer{redact: redact}
+}
+
+// maxLineLength splits longer lines so that a goction printing without newlines cannot exhaust memory
+const maxLineLength = 64 * 1024
+
+// Writer stores the output of one execution. It is safe for concurrent use.
+type Writer struct {
+	mu        sync.Mutex
+	file      *os.File
+	encoder   *json.Encoder
+	size      int64
+	maxSize   int64
+	truncated bool
+	redact    func(string) string
+	// mirror receives a copy of every line, if set
+	mirror io.Writer
+}
+
+// Mirror also writes every line to w, prefixed with its stream
+func (w *Writer) Mirror(out io.Writer) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.mirror = out
+}
+
+// Line stores one line of output
+func (w *Writer) Line(stream, text string) {
+	if w.redact != nil {
+		text = w.redact(text)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.mirror != nil {
+		fmt.Fprintf(w.mirror, "[%s] %s\n", stream, text)
+	}
+	if w.truncated || w.file == nil {
+		return
+	}
+	if w.maxSize > 0 && w.size+int64(len(text)) > w.maxSize {
+		w.truncated = true
+		stream, text = StreamStderr, fmt.Sprintf("[output truncated after %d bytes]", w.size)
+	}
+	w.size += int64(len(text))
+	w.encoder.Encode(Line{Time: time.Now(), Stream: stream, Text: text})
+}
+
+// Stream returns a writer storing what is written to it line by line in stream.
+// It must be closed to store the last line if it does not end with a newline.
+func (w *Writer) Stream(stream string) io.WriteCloser {
+	return &streamWriter{out: w, stream: stream}
+}
+
+// Close closes the output file
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	return w.file.Close()
+}
+
+type streamWriter struct {
+	out    *Writer
+	stream string
+	buf    []byte
+}
+
+func (s *streamWriter) Write(p []byte) (int, error) {
+	s.buf = append(s.buf, p...)
+	for {
+		i := bytes.IndexByte(s.buf, '\n')
+		if i < 0 {
+			if len(s.buf) < maxLineLength {
+				return len(p), nil
+			}
+			i = maxLineLength
+			s.out.Line(s.stream, string(s.buf[:i]))
+			s.buf = s.buf[i:]
+			continue
+		}
+		s.out.Line(s.stream, strings.TrimSuffix(string(s.buf[:i]), "\r"))
+		s.buf = s.buf[i+1:]
+	}
+}
+
+func (s *streamWriter) Close() error {
+	if len(s.buf) > 0 {
+		s.out.Line(s.stream, string(s.buf))
+		s.buf = nil
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+//go:build windows || plan9
+
+// Package filelock serializes access to files shared by the goction processes (the server and CLI commands)
+// with advisory locks on a companion lock file.
+package filelock
+
+// File does not lock: concurrent access by several processes is not prevented on this platform
+type File struct{}
+
+// Open returns a lock file that does not lock
+func Open(path string) (*File, error) {
+	return &File{}, nil
+}
+
+// Lock does not lock
+func (l *File) Lock() error { return nil }
+
+// RLock does not lock
+func (l *File) RLock() error { return nil }
+
+// Unlock does nothing
+func (l *File) Unlock() error { return nil }
+
+// Close does nothing
+func (l *File) Close() error { return nil }
+
+// Lock does not lock: concurrent access by several processes is not prevented on this platform
+func Lock(path string) (func(), error) {
+	return func() {}, nil
+}
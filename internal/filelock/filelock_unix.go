@@ -0,0 +1,61 @@
+//go:build !windows && !plan9
+
+// Package filelock serializes access to files shared by the goction processes (the server and CLI commands)
+// with advisory locks on a companion lock file.
+package filelock
+
+import (
+	"os"
+	"syscall"
+)
+
+// File is a lock file kept open to be locked repeatedly
+type File struct {
+	file *os.File
+}
+
+// Open opens the lock file at path, creating it if needed
+func Open(path string) (*File, error) {
+	// Opening the lock file read-only is enough to lock it, so users who cannot write it can still take the lock
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &File{file: file}, nil
+}
+
+// Lock takes an exclusive lock, converting the shared lock held through l if any
+func (l *File) Lock() error {
+	return syscall.Flock(int(l.file.Fd()), syscall.LOCK_EX)
+}
+
+// RLock takes a shared lock, held along with those of other readers
+func (l *File) RLock() error {
+	return syscall.Flock(int(l.file.Fd()), syscall.LOCK_SH)
+}
+
+// Unlock releases the lock held through l
+func (l *File) Unlock() error {
+	return syscall.Flock(int(l.file.Fd()), syscall.LOCK_UN)
+}
+
+// Close closes the lock file, releasing its lock
+func (l *File) Close() error {
+	return l.file.Close()
+}
+
+// Lock takes an exclusive lock on path, creating it if needed, and returns the function releasing it
+func Lock(path string) (func(), error) {
+	l, err := Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := l.Lock(); err != nil {
+		l.Close()
+		return nil, err
+	}
+	return func() {
+		l.Unlock()
+		l.Close()
+	}, nil
+}
@@ -0,0 +1,145 @@
+// Package goctions manages the goctions installed in the goctions directory: creating, importing and removing them,
+// building their plugin, editing their source files and keeping the revisions saved from the dashboard.
+package goctions
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned for a goction that is not installed
+var ErrNotFound = errors.New("goction does not exist")
+
+// BuildError is a compiler error located in a source file of a goction
+type BuildError struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// BuildResult is the outcome of a build
+type BuildResult struct {
+	Success bool   `json:"success"`
+	Output  string `json:"output"`
+	// Errors are the compiler errors found in the output, in order
+	Errors   []BuildError  `json:"errors,omitempty"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Dir returns the directory of the goction name, or ErrNotFound if it is not installed
+func Dir(goctionsDir, name string) (string, error) {
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return "", fmt.Errorf("%w %q", ErrInvalidName, name)
+	}
+	dir := filepath.Join(goctionsDir, name)
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return dir, nil
+}
+
+// Build compiles the plugin of a goction. If output is set, it receives a copy of the compiler output as it is produced.
+// A goction that does not compile gives a result whose Success is false and a nil error; the error reports builds
+// that could not run. The previous plugin is only replaced by a successful build.
+func Build(ctx context.Context, goctionsDir, name string, output io.Writer) (*BuildResult, error) {
+	dir, err := Dir(goctionsDir, name)
+	if err != nil {
+		return nil, err
+	}
+	defer lock(dir)()
+	return build(ctx, dir, name, output)
+}
+
+// build compiles the plugin of the goction stored in dir; the caller holds the lock of dir
+func build(ctx context.Context, dir, name string, output io.Writer) (*BuildResult, error) {
+	// Building next to the plugin and renaming it keeps running executions from opening a partly written plugin
+	plugin := filepath.Join(dir, name+".so")
+	tmp := plugin + ".build"
+	defer os.Remove(tmp)
+
+	var combined bytes.Buffer
+	var out io.Writer = &combined
+	if output != nil {
+		out = io.MultiWriter(&combined, output)
+	}
+
+	cmd := goCommand(ctx, dir, "build", "-buildmode=plugin", "-o", tmp, ".")
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	start := time.Now()
+	err := cmd.Run()
+	result := &BuildResult{
+		Output:   combined.String(),
+		Errors:   ParseBuildErrors(combined.String()),
+		Duration: time.Since(start),
+	}
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr) && ctx.Err() == nil:
+		return result, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to build goction: %w", err)
+	}
+	if err := os.Rename(tmp, plugin); err != nil {
+		return nil, fmt.Errorf("failed to install goction plugin: %w", err)
+	}
+	result.Success = true
+	return result, nil
+}
+
+// goCommand prepares the go command to run in dir
+func goCommand(ctx context.Context, dir string, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "PATH="+os.Getenv("PATH")+":/usr/local/go/bin")
+	return cmd
+}
+
+// buildErrorPattern matches the "file.go:line:column: message" lines of the go command
+var buildErrorPattern = regexp.MustCompile(`^(?:\./)?([^\s:]+\.go):(\d+)(?::(\d+))?: (.+)$`)
+
+// ParseBuildErrors extracts the compiler errors from the output of go build.
+// Indented lines following an error are appended to its message.
+func ParseBuildErrors(output string) []BuildError {
+	var errs []BuildError
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := buildErrorPattern.FindStringSubmatch(line); m != nil {
+			buildErr := BuildError{File: filepath.ToSlash(m[1]), Message: m[4]}
+			buildErr.Line, _ = strconv.Atoi(m[2])
+			buildErr.Column, _ = strconv.Atoi(m[3])
+			errs = append(errs, buildErr)
+			continue
+		}
+		if len(errs) > 0 && strings.HasPrefix(line, "\t") {
+			errs[len(errs)-1].Message += "\n" + strings.TrimPrefix(line, "\t")
+		}
+	}
+	return errs
+}
+
+// locks serializes the builds and saves of each goction directory
+var locks sync.Map
+
+// lock locks the goction stored in dir and returns the function unlocking it
+func lock(dir string) func() {
+	mu, _ := locks.LoadOrStore(dir, &sync.Mutex{})
+	mu.(*sync.Mutex).Lock()
+	return mu.(*sync.Mutex).Unlock
+}
@@ -0,0 +1,242 @@
+package goctions
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"goction/internal/manifest"
+	"goction/pkg/goctionutil"
+)
+
+// ErrExists is returned when creating or importing a goction that is already installed
+var ErrExists = errors.New("goction already exists")
+
+// ErrInvalidName is returned for a name that cannot be used for a goction
+var ErrInvalidName = errors.New("invalid goction name")
+
+// ErrInvalidArchive is returned for an archive whose files cannot be extracted into a goction
+var ErrInvalidArchive = errors.New("invalid archive")
+
+// Limits of goction archives, checked against the extracted content rather than the sizes the archive declares
+const (
+	// MaxArchiveFileSize is the largest file extracted from a goction archive
+	MaxArchiveFileSize = 64 << 20
+	// MaxArchiveSize is the largest total size of the files extracted from a goction archive
+	MaxArchiveSize = 128 << 20
+	// MaxArchiveEntries is the largest number of entries of a goction archive
+	MaxArchiveEntries = 1000
+)
+
+// newNamePattern restricts the names of new goctions to Go identifiers, so that the template exports the symbol
+// the runner looks up
+var newNamePattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// Create creates the goction name from the template: its main.go, its manifest and its go.mod
+func Create(ctx context.Context, goctionsDir, name, description string) (err error) {
+	if !newNamePattern.MatchString(name) {
+		return fmt.Errorf("%w %q: use letters, digits and underscores, starting with a letter", ErrInvalidName, name)
+	}
+	dir, err := makeDir(goctionsDir, name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			os.RemoveAll(dir)
+		}
+	}()
+
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(goctionutil.GenerateGoctionTemplate(name)), 0644); err != nil {
+		return fmt.Errorf("failed to write goction file: %w", err)
+	}
+
+	if description == "" {
+		description = fmt.Sprintf("%s goction", name)
+	}
+	m := &manifest.Manifest{
+		Description: description,
+		Version:     "0.1.0",
+	}
+	if err := m.Save(dir); err != nil {
+		return err
+	}
+
+	if output, err := goCommand(ctx, dir, "mod", "init", "goction/"+name).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to initialize go.mod: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// Import extracts a zip archive, as written by goction export, into the new goction name
+func Import(goctionsDir, name string, archive *zip.Reader) (err error) {
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return fmt.Errorf("%w %q", ErrInvalidName, name)
+	}
+	budget, err := newArchiveBudget(archive)
+	if err != nil {
+		return err
+	}
+	dir, err := makeDir(goctionsDir, name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			os.RemoveAll(dir)
+		}
+	}()
+
+	for _, file := range archive.File {
+		// Entries must stay inside the goction directory
+		path := filepath.Join(dir, filepath.FromSlash(file.Name))
+		if !strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return fmt.Errorf("%w: path %s is outside the goction", ErrInvalidArchive, file.Name)
+		}
+
+		if file.FileInfo().IsDir() {
+			if err := os.MkdirAll(path, 0775); err != nil {
+				return fmt.Errorf("failed to create directory: %w", err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0775); err != nil {
+			return fmt.Errorf("failed to create directory: %w", err)
+		}
+		if err := extract(budget, file, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// makeDir creates the directory of the new goction name
+func makeDir(goctionsDir, name string) (string, error) {
+	if err := os.MkdirAll(goctionsDir, 0775); err != nil {
+		return "", fmt.Errorf("failed to create goctions directory: %w", err)
+	}
+	dir := filepath.Join(goctionsDir, name)
+	if err := os.Mkdir(dir, 0775); os.IsExist(err) {
+		return "", fmt.Errorf("%w: %s", ErrExists, name)
+	} else if err != nil {
+		return "", fmt.Errorf("failed to create goction directory: %w", err)
+	}
+	return dir, nil
+}
+
+// archiveBudget is what is left of the limits of an archive as its files are read
+type archiveBudget struct {
+	remaining int64
+}
+
+func newArchiveBudget(archive *zip.Reader) (*archiveBudget, error) {
+	if len(archive.File) > MaxArchiveEntries {
+		return nil, fmt.Errorf("%w: more than %d entries", ErrInvalidArchive, MaxArchiveEntries)
+	}
+	return &archiveBudget{remaining: MaxArchiveSize}, nil
+}
+
+// copy copies the content of file to dst, failing beyond MaxArchiveFileSize or once the files of the archive
+// add up to more than MaxArchiveSize
+func (b *archiveBudget) copy(dst io.Writer, file *zip.File) error {
+	src, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open %s in archive: %w", file.Name, err)
+	}
+	defer src.Close()
+
+	limit := min(MaxArchiveFileSize, b.remaining)
+	n, err := io.Copy(dst, io.LimitReader(src, limit+1))
+	b.remaining -= n
+	if err != nil {
+		return fmt.Errorf("failed to extract %s: %w", file.Name, err)
+	}
+	if n > MaxArchiveFileSize {
+		return fmt.Errorf("%w: %s is larger than %d bytes", ErrInvalidArchive, file.Name, MaxArchiveFileSize)
+	}
+	if b.remaining < 0 {
+		return fmt.Errorf("%w: the extracted files are larger than %d bytes", ErrInvalidArchive, MaxArchiveSize)
+	}
+	return nil
+}
+
+// extract writes a file of an archive to path
+func extract(budget *archiveBudget, file *zip.File, path string) error {
+	dst, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, file.Mode().Perm())
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	err = budget.copy(dst, file)
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// ArchiveSources returns the source files at the top of a zip archive; its other entries are ignored
+func ArchiveSources(archive *zip.Reader) ([]File, error) {
+	budget, err := newArchiveBudget(archive)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []File
+	for _, file := range archive.File {
+		if file.FileInfo().IsDir() || !IsSourceFile(file.Name) {
+			continue
+		}
+		var content strings.Builder
+		if err := budget.copy(&content, file); err != nil {
+			return nil, err
+		}
+		files = append(files, File{Name: file.Name, Content: content.String()})
+	}
+	return files, nil
+}
+
+// WriteSources writes files into a goction without building it.
+// When replace is set, the Go files of the goction missing from files are removed.
+func WriteSources(goctionsDir, name string, files []File, replace bool) error {
+	dir, err := Dir(goctionsDir, name)
+	if err != nil {
+		return err
+	}
+	defer lock(dir)()
+	return writeSources(dir, files, replace)
+}
+
+// Remove deletes a goction with its plugin and revisions
+func Remove(goctionsDir, name string) error {
+	dir, err := Dir(goctionsDir, name)
+	if err != nil {
+		return err
+	}
+	defer lock(dir)()
+
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove goction: %w", err)
+	}
+	return nil
+}
+
+// SetDisabled disables or enables a goction in its manifest. A disabled goction is not executed.
+func SetDisabled(goctionsDir, name string, disabled bool) error {
+	dir, err := Dir(goctionsDir, name)
+	if err != nil {
+		return err
+	}
+	defer lock(dir)()
+
+	m, err := manifest.Load(dir)
+	if err != nil {
+		return err
+	}
+	m.Disabled = disabled
+	return m.Save(dir)
+}
@@ -0,0 +1,195 @@
+package goctions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// RevisionsDir is the directory of a goction holding its saved revisions.
+// The go command ignores directories starting with a dot, so revisions are not compiled.
+const RevisionsDir = ".revisions"
+
+// MaxRevisions is the number of revisions kept per goction; the oldest ones are deleted on save
+const MaxRevisions = 50
+
+// ErrRevisionNotFound is returned for a revision that does not exist
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// revisionIDFormat names revisions after their UTC time, so that they sort by name
+const revisionIDFormat = "20060102T150405.000000Z"
+
+var revisionIDPattern = regexp.MustCompile(`^\d{8}T\d{6}\.\d{6}Z$`)
+
+// Revision is a snapshot of the source files of a goction taken when they were saved
+type Revision struct {
+	ID     string    `json:"id"`
+	Time   time.Time `json:"time"`
+	Author string    `json:"author,omitempty"`
+	// RestoredFrom is the revision whose files were restored by this one, if any
+	RestoredFrom string `json:"restored_from,omitempty"`
+	// Built is whether the build that followed the save succeeded
+	Built bool   `json:"built"`
+	Files []File `json:"files,omitempty"`
+}
+
+// Save writes files into a goction, builds it and records its source files as a new revision by author.
+// Source files that are not listed are left unchanged. A revision is recorded even if the goction does not compile.
+func Save(ctx context.Context, goctionsDir, name, author string, files []File) (Revision, *BuildResult, error) {
+	dir, err := Dir(goctionsDir, name)
+	if err != nil {
+		return Revision{}, nil, err
+	}
+	defer lock(dir)()
+
+	if err := writeSources(dir, files, false); err != nil {
+		return Revision{}, nil, err
+	}
+	return commit(ctx, dir, name, Revision{Author: author})
+}
+
+// Restore brings the source files of a goction back to a revision, builds it and records the result as a new revision.
+// Go files added since the revision are removed.
+func Restore(ctx context.Context, goctionsDir, name, id, author string) (Revision, *BuildResult, error) {
+	dir, err := Dir(goctionsDir, name)
+	if err != nil {
+		return Revision{}, nil, err
+	}
+	defer lock(dir)()
+
+	previous, err := readRevision(dir, id)
+	if err != nil {
+		return Revision{}, nil, err
+	}
+	if err := writeSources(dir, previous.Files, true); err != nil {
+		return Revision{}, nil, err
+	}
+	return commit(ctx, dir, name, Revision{Author: author, RestoredFrom: previous.ID})
+}
+
+// commit builds the goction stored in dir and records its source files as the revision rev
+func commit(ctx context.Context, dir, name string, rev Revision) (Revision, *BuildResult, error) {
+	files, err := readSources(dir)
+	if err != nil {
+		return Revision{}, nil, err
+	}
+	rev.Files = files
+
+	result, buildErr := build(ctx, dir, name, nil)
+	rev.Built = result != nil && result.Success
+	if err := storeRevision(dir, &rev); err != nil {
+		return Revision{}, nil, err
+	}
+	if buildErr != nil {
+		return rev, nil, buildErr
+	}
+	return rev, result, nil
+}
+
+// ListRevisions returns the revisions of a goction, newest first, without their files
+func ListRevisions(goctionsDir, name string) ([]Revision, error) {
+	dir, err := Dir(goctionsDir, name)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := revisionIDs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := make([]Revision, 0, len(ids))
+	for i := len(ids) - 1; i >= 0; i-- {
+		rev, err := readRevision(dir, ids[i])
+		if err != nil {
+			return nil, err
+		}
+		rev.Files = nil
+		revisions = append(revisions, rev)
+	}
+	return revisions, nil
+}
+
+func readRevision(dir, id string) (Revision, error) {
+	if !revisionIDPattern.MatchString(id) {
+		return Revision{}, fmt.Errorf("%w: %s", ErrRevisionNotFound, id)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, RevisionsDir, id+".json"))
+	if os.IsNotExist(err) {
+		return Revision{}, fmt.Errorf("%w: %s", ErrRevisionNotFound, id)
+	}
+	if err != nil {
+		return Revision{}, fmt.Errorf("failed to read revision %s: %w", id, err)
+	}
+
+	var rev Revision
+	if err := json.Unmarshal(data, &rev); err != nil {
+		return Revision{}, fmt.Errorf("failed to decode revision %s: %w", id, err)
+	}
+	return rev, nil
+}
+
+// storeRevision assigns an ID and a time to rev, writes it and deletes the revisions beyond MaxRevisions
+func storeRevision(dir string, rev *Revision) error {
+	revisionsDir := filepath.Join(dir, RevisionsDir)
+	if err := os.MkdirAll(revisionsDir, 0775); err != nil {
+		return fmt.Errorf("failed to create revisions directory: %w", err)
+	}
+
+	rev.Time = time.Now().UTC().Truncate(time.Microsecond)
+	rev.ID = rev.Time.Format(revisionIDFormat)
+	for {
+		if _, err := os.Stat(filepath.Join(revisionsDir, rev.ID+".json")); os.IsNotExist(err) {
+			break
+		}
+		rev.Time = rev.Time.Add(time.Microsecond)
+		rev.ID = rev.Time.Format(revisionIDFormat)
+	}
+
+	data, err := json.Marshal(rev)
+	if err != nil {
+		return fmt.Errorf("failed to encode revision: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(revisionsDir, rev.ID+".json"), data, 0644); err != nil {
+		return fmt.Errorf("failed to write revision: %w", err)
+	}
+
+	ids, err := revisionIDs(dir)
+	if err != nil {
+		return err
+	}
+	for len(ids) > MaxRevisions {
+		if err := os.Remove(filepath.Join(revisionsDir, ids[0]+".json")); err != nil {
+			return fmt.Errorf("failed to delete revision %s: %w", ids[0], err)
+		}
+		ids = ids[1:]
+	}
+	return nil
+}
+
+// revisionIDs returns the IDs of the revisions stored in dir, oldest first
+func revisionIDs(dir string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(dir, RevisionsDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read revisions: %w", err)
+	}
+
+	var ids []string
+	for _, entry := range entries {
+		id := strings.TrimSuffix(entry.Name(), ".json")
+		if revisionIDPattern.MatchString(id) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
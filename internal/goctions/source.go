@@ -0,0 +1,96 @@
+package goctions
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"goction/internal/manifest"
+)
+
+// File is a source file of a goction
+type File struct {
+	Name    string `json:"name"`
+	Content string `json:"content"`
+}
+
+// IsSourceFile reports whether name is a source file of a goction: a Go file, go.mod, go.sum or the manifest.
+// Source files are stored at the top of the goction directory.
+func IsSourceFile(name string) bool {
+	if name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return false
+	}
+	switch name {
+	case "go.mod", "go.sum", manifest.FileName:
+		return true
+	}
+	return strings.HasSuffix(name, ".go")
+}
+
+// ReadSources returns the source files of a goction: main.go first, then the other files by name
+func ReadSources(goctionsDir, name string) ([]File, error) {
+	dir, err := Dir(goctionsDir, name)
+	if err != nil {
+		return nil, err
+	}
+	return readSources(dir)
+}
+
+func readSources(dir string) ([]File, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read goction directory: %w", err)
+	}
+
+	var files []File
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || !IsSourceFile(entry.Name()) {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", entry.Name(), err)
+		}
+		files = append(files, File{Name: entry.Name(), Content: string(content)})
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].Name == "main.go" && files[j].Name != "main.go"
+	})
+	return files, nil
+}
+
+// writeSources writes files into dir. When replace is set, the Go files of dir missing from files are removed;
+// go.mod, go.sum and the manifest are only changed when they are listed.
+func writeSources(dir string, files []File, replace bool) error {
+	keep := make(map[string]bool, len(files))
+	for _, file := range files {
+		if !IsSourceFile(file.Name) {
+			return fmt.Errorf("%q is not a source file of a goction", file.Name)
+		}
+		keep[file.Name] = true
+	}
+
+	for _, file := range files {
+		if err := os.WriteFile(filepath.Join(dir, file.Name), []byte(file.Content), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file.Name, err)
+		}
+	}
+	if !replace {
+		return nil
+	}
+
+	current, err := readSources(dir)
+	if err != nil {
+		return err
+	}
+	for _, file := range current {
+		if !keep[file.Name] && strings.HasSuffix(file.Name, ".go") {
+			if err := os.Remove(filepath.Join(dir, file.Name)); err != nil {
+				return fmt.Errorf("failed to remove %s: %w", file.Name, err)
+			}
+		}
+	}
+	return nil
+}
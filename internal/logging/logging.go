@@ -0,0 +1,100 @@
+// Package logging builds the logger shared by the goction commands and the server.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"goction/internal/config"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Fields added to log lines about requests and executions
+const (
+	FieldRequestID   = "request_id"
+	FieldGoction     = "goction"
+	FieldExecutionID = "execution_id"
+)
+
+// SyslogTag identifies goction in syslog and the journal
+const SyslogTag = "goction"
+
+// New creates a logger writing to the outputs of the configuration with its level and format
+func New(cfg *config.Config) (*logrus.Logger, error) {
+	logger := logrus.New()
+	if err := Apply(logger, cfg); err != nil {
+		return nil, err
+	}
+
+	var writers []io.Writer
+	for _, output := range cfg.Log.Outputs {
+		switch output {
+		case config.LogOutputFile:
+			file, err := OpenRotatingFile(cfg.LogFile, cfg.Log.Rotation)
+			if err != nil {
+				return nil, err
+			}
+			writers = append(writers, file)
+		case config.LogOutputStdout:
+			writers = append(writers, os.Stdout)
+		case config.LogOutputStderr:
+			writers = append(writers, os.Stderr)
+		case config.LogOutputSyslog:
+			hook, err := newSyslogHook()
+			if err != nil {
+				return nil, fmt.Errorf("failed to connect to syslog: %w", err)
+			}
+			logger.AddHook(hook)
+		case config.LogOutputJournald:
+			hook, err := newJournaldHook()
+			if err != nil {
+				return nil, fmt.Errorf("failed to connect to journald: %w", err)
+			}
+			logger.AddHook(hook)
+		default:
+			return nil, fmt.Errorf("unknown log output %q", output)
+		}
+	}
+	// syslog and journald receive entries through hooks, which do not use the logger output
+	logger.SetOutput(io.MultiWriter(writers...))
+
+	return logger, nil
+}
+
+// Apply sets the level and format of the configuration on logger.
+// The server calls it when the configuration is reloaded; outputs require a restart.
+func Apply(logger *logrus.Logger, cfg *config.Config) error {
+	level, err := logrus.ParseLevel(cfg.Log.Level)
+	if err != nil {
+		return err
+	}
+	logger.SetLevel(level)
+
+	switch cfg.Log.Format {
+	case config.LogFormatJSON:
+		logger.SetFormatter(&logrus.JSONFormatter{})
+	case config.LogFormatText:
+		logger.SetFormatter(&logrus.TextFormatter{FullTimestamp: true})
+	default:
+		return fmt.Errorf("unknown log format %q", cfg.Log.Format)
+	}
+	return nil
+}
+
+type contextKey struct{}
+
+// WithEntry returns a context carrying entry, so that handlers log with the fields of their request
+func WithEntry(ctx context.Context, entry *logrus.Entry) context.Context {
+	return context.WithValue(ctx, contextKey{}, entry)
+}
+
+// FromContext returns the entry stored in ctx by WithEntry, or a new entry of logger
+func FromContext(ctx context.Context, logger *logrus.Logger) *logrus.Entry {
+	if entry, ok := ctx.Value(contextKey{}).(*logrus.Entry); ok {
+		return entry
+	}
+	return logrus.NewEntry(logger)
+}
@@ -0,0 +1,171 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"goction/internal/stats"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Entry is a parsed log line
+type Entry struct {
+	Time    time.Time         `json:"time"`
+	Level   string            `json:"level,omitempty"`
+	Message string            `json:"msg"`
+	Fields  map[string]string `json:"fields,omitempty"`
+	// Raw is the line as written in the log file
+	Raw string `json:"raw"`
+}
+
+// ParseLine parses a line written with the text or the JSON format.
+// Lines in neither format are returned with their text as message and no time or level.
+func ParseLine(line string) Entry {
+	entry := Entry{Message: line, Raw: line, Fields: map[string]string{}}
+
+	var values map[string]string
+	if strings.HasPrefix(line, "{") {
+		var doc map[string]interface{}
+		if json.Unmarshal([]byte(line), &doc) != nil {
+			return entry
+		}
+		values = make(map[string]string, len(doc))
+		for key, value := range doc {
+			if s, ok := value.(string); ok {
+				values[key] = s
+			} else {
+				values[key] = fmt.Sprint(value)
+			}
+		}
+	} else {
+		values = parseLogfmt(line)
+		if values["level"] == "" {
+			return entry
+		}
+	}
+
+	for key, value := range values {
+		switch key {
+		case "time":
+			entry.Time, _ = time.Parse(time.RFC3339, value)
+		case "level":
+			entry.Level = value
+		case "msg":
+			entry.Message = value
+		default:
+			entry.Fields[key] = value
+		}
+	}
+	return entry
+}
+
+// parseLogfmt parses the key=value pairs written by the logrus text formatter
+func parseLogfmt(line string) map[string]string {
+	values := make(map[string]string)
+	for len(line) > 0 {
+		line = strings.TrimLeft(line, " ")
+		eq := strings.IndexByte(line, '=')
+		if eq <= 0 || strings.ContainsRune(line[:eq], ' ') {
+			return values
+		}
+		key := line[:eq]
+		line = line[eq+1:]
+
+		var value string
+		if strings.HasPrefix(line, `"`) {
+			end := closingQuote(line)
+			if end < 0 {
+				return values
+			}
+			var err error
+			// The text formatter quotes values with %q
+			if value, err = strconv.Unquote(line[:end+1]); err != nil {
+				value = line[1:end]
+			}
+			line = line[end+1:]
+		} else {
+			end := strings.IndexByte(line, ' ')
+			if end < 0 {
+				end = len(line)
+			}
+			value, line = line[:end], line[end:]
+		}
+		values[key] = value
+	}
+	return values
+}
+
+// closingQuote returns the index of the quote ending the quoted string at the start of s
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+// Filter selects log entries. Zero fields match every entry.
+type Filter struct {
+	Goction string
+	// Level is the least severe level shown
+	Level logrus.Level
+	Since time.Time
+	Grep  *regexp.Regexp
+}
+
+// NewFilter builds a filter from its textual form: a level name, a time for since as accepted by
+// stats.ParseTime, and a regular expression for grep
+func NewFilter(goction, level, since, grep string) (Filter, error) {
+	filter := Filter{Goction: goction, Level: logrus.TraceLevel}
+	if level != "" {
+		parsed, err := logrus.ParseLevel(level)
+		if err != nil {
+			return filter, err
+		}
+		filter.Level = parsed
+	}
+	if since != "" {
+		t, err := stats.ParseTime(since, time.Now())
+		if err != nil {
+			return filter, err
+		}
+		filter.Since = t
+	}
+	if grep != "" {
+		re, err := regexp.Compile(grep)
+		if err != nil {
+			return filter, fmt.Errorf("invalid grep expression: %w", err)
+		}
+		filter.Grep = re
+	}
+	return filter, nil
+}
+
+// Match reports whether entry passes the filter
+func (f Filter) Match(entry Entry) bool {
+	if f.Goction != "" && entry.Fields[FieldGoction] != f.Goction {
+		return false
+	}
+	if f.Level < logrus.TraceLevel {
+		level, err := logrus.ParseLevel(entry.Level)
+		if err != nil || level > f.Level {
+			return false
+		}
+	}
+	if !f.Since.IsZero() && entry.Time.Before(f.Since) {
+		return false
+	}
+	if f.Grep != nil && !f.Grep.MatchString(entry.Raw) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,270 @@
+package logging
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"goction/internal/config"
+	"goction/internal/filelock"
+)
+
+// backupTimeFormat names rotated files, e.g. goction-20240102T150405.000.log
+const backupTimeFormat = "20060102T150405.000"
+
+// RotatingFile is a log file rotated when it exceeds a size or at the start of each period.
+// Rotated files are optionally compressed and removed beyond a count or an age.
+//
+// Several processes (the server and CLI commands) may write to the same file. Writers hold a shared lock on
+// a lock file, and the rotation is done by one of them under the exclusive lock, so no line is written to
+// a file being rotated. The others reopen the file when they notice that it was replaced.
+type RotatingFile struct {
+	path     string
+	rotation config.LogRotation
+	lock     *filelock.File
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+
+	// compressing tracks the rotated files being compressed in the background
+	compressing sync.WaitGroup
+}
+
+// OpenRotatingFile opens path for appending, creating its directory if needed
+func OpenRotatingFile(path string, rotation config.LogRotation) (*RotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	lock, err := filelock.Open(path + ".lock")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log lock file: %w", err)
+	}
+	f := &RotatingFile{path: path, rotation: rotation, lock: lock}
+	if err := f.open(); err != nil {
+		lock.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// Write appends p to the current file, rotating it first if needed
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.lock.RLock(); err != nil {
+		return 0, fmt.Errorf("failed to lock %s: %w", f.path, err)
+	}
+	defer f.lock.Unlock()
+
+	if f.replaced() {
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+	if f.needsRotation(int64(len(p))) {
+		if err := f.rotate(int64(len(p))); err != nil {
+			fmt.Fprintf(os.Stderr, "goction: failed to rotate %s: %v\n", f.path, err)
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Close closes the current file once the rotated files are compressed
+func (f *RotatingFile) Close() error {
+	f.compressing.Wait()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.lock.Close()
+	return f.file.Close()
+}
+
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	if f.file != nil {
+		f.file.Close()
+	}
+	f.file, f.size = file, info.Size()
+	return nil
+}
+
+// replaced reports whether another process rotated the file since it was opened
+func (f *RotatingFile) replaced() bool {
+	current, err := os.Stat(f.path)
+	if err != nil {
+		return true
+	}
+	opened, err := f.file.Stat()
+	if err != nil {
+		return true
+	}
+	if !os.SameFile(current, opened) {
+		return true
+	}
+	// Keep track of what the other writers appended
+	f.size = opened.Size()
+	return false
+}
+
+func (f *RotatingFile) needsRotation(n int64) bool {
+	if f.size == 0 {
+		return false
+	}
+	if max := int64(f.rotation.MaxSizeMB) << 20; max > 0 && f.size+n > max {
+		return true
+	}
+	if f.rotation.Interval != "" {
+		if info, err := f.file.Stat(); err == nil {
+			return periodStart(info.ModTime(), f.rotation.Interval).Before(periodStart(time.Now(), f.rotation.Interval))
+		}
+	}
+	return false
+}
+
+// periodStart truncates t to the start of its rotation period in local time
+func periodStart(t time.Time, interval string) time.Time {
+	switch interval {
+	case config.LogRotateHourly:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+	case config.LogRotateDaily:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	}
+	return t
+}
+
+// rotate renames the current file, unless another process rotated it in the meantime.
+// The shared lock held by Write is converted to the exclusive lock until Write returns.
+// The rotated file is compressed and the old ones pruned in the background.
+func (f *RotatingFile) rotate(n int64) error {
+	// Other processes may have been waiting to convert their lock as well
+	if err := f.lock.Lock(); err != nil {
+		return err
+	}
+
+	if f.replaced() {
+		if err := f.open(); err != nil {
+			return err
+		}
+		if !f.needsRotation(n) {
+			return nil
+		}
+	}
+
+	backup := backupName(f.path, time.Now())
+	if err := os.Rename(f.path, backup); err != nil {
+		return err
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	f.compressing.Add(1)
+	go func() {
+		defer f.compressing.Done()
+		if f.rotation.Compress {
+			if err := compress(backup); err != nil {
+				fmt.Fprintf(os.Stderr, "goction: %v\n", err)
+			}
+		}
+		if err := f.prune(); err != nil {
+			fmt.Fprintf(os.Stderr, "goction: failed to remove the old rotated files of %s: %v\n", f.path, err)
+		}
+	}()
+	return nil
+}
+
+// backupName inserts the rotation time before the extension of path
+func backupName(path string, t time.Time) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + "-" + t.Format(backupTimeFormat) + ext
+}
+
+// compress replaces path with path.gz
+func compress(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmp := path + ".gz.tmp"
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	_, err = io.Copy(zw, src)
+	if err == nil {
+		err = zw.Close()
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path+".gz")
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to compress %s: %w", path, err)
+	}
+	return os.Remove(path)
+}
+
+// Backups returns the rotated files of path, newest first
+func Backups(path string) ([]string, error) {
+	ext := filepath.Ext(path)
+	matches, err := filepath.Glob(strings.TrimSuffix(path, ext) + "-*" + ext + "*")
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []string
+	for _, match := range matches {
+		if !strings.HasSuffix(match, ".tmp") {
+			backups = append(backups, match)
+		}
+	}
+	// The rotation time sorts lexically
+	sort.Sort(sort.Reverse(sort.StringSlice(backups)))
+	return backups, nil
+}
+
+// prune removes the rotated files beyond MaxFiles or older than MaxAgeDays
+func (f *RotatingFile) prune() error {
+	backups, err := Backups(f.path)
+	if err != nil {
+		return err
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -f.rotation.MaxAgeDays)
+	for i, backup := range backups {
+		remove := f.rotation.MaxFiles > 0 && i >= f.rotation.MaxFiles
+		if !remove && f.rotation.MaxAgeDays > 0 {
+			if info, err := os.Stat(backup); err == nil && info.ModTime().Before(cutoff) {
+				remove = true
+			}
+		}
+		if remove {
+			os.Remove(backup)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+//go:build windows || plan9
+
+package logging
+
+import (
+	"errors"
+
+	"github.com/sirupsen/logrus"
+)
+
+func newSyslogHook() (logrus.Hook, error) {
+	return nil, errors.New("syslog is not supported on this platform")
+}
+
+func newJournaldHook() (logrus.Hook, error) {
+	return nil, errors.New("journald is not supported on this platform")
+}
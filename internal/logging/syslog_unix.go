@@ -0,0 +1,100 @@
+//go:build !windows && !plan9
+
+package logging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"log/syslog"
+	"net"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	logrussyslog "github.com/sirupsen/logrus/hooks/syslog"
+)
+
+// journaldSocket receives entries in the native journal protocol
+const journaldSocket = "/run/systemd/journal/socket"
+
+func newSyslogHook() (logrus.Hook, error) {
+	return logrussyslog.NewSyslogHook("", "", syslog.LOG_INFO|syslog.LOG_DAEMON, SyslogTag)
+}
+
+// journaldHook sends entries to journald with their fields as journal fields,
+// so that they can be queried with journalctl GOCTION=<name> EXECUTION_ID=<id>
+type journaldHook struct {
+	conn *net.UnixConn
+}
+
+func newJournaldHook() (logrus.Hook, error) {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: journaldSocket, Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+	return &journaldHook{conn: conn}, nil
+}
+
+func (h *journaldHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *journaldHook) Fire(entry *logrus.Entry) error {
+	var buf bytes.Buffer
+	writeJournalField(&buf, "MESSAGE", entry.Message)
+	writeJournalField(&buf, "PRIORITY", fmt.Sprint(journalPriority(entry.Level)))
+	writeJournalField(&buf, "SYSLOG_IDENTIFIER", SyslogTag)
+	for key, value := range entry.Data {
+		if name := journalFieldName(key); name != "" {
+			writeJournalField(&buf, name, fmt.Sprint(value))
+		}
+	}
+	_, err := h.conn.Write(buf.Bytes())
+	return err
+}
+
+// writeJournalField encodes a field, using the binary form for values spanning several lines
+func writeJournalField(buf *bytes.Buffer, name, value string) {
+	if !strings.Contains(value, "\n") {
+		fmt.Fprintf(buf, "%s=%s\n", name, value)
+		return
+	}
+	buf.WriteString(name)
+	buf.WriteByte('\n')
+	binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
+
+// journalFieldName converts a logrus field to a journal field name: upper case letters, digits and underscores,
+// not starting with an underscore, which is reserved to fields set by journald
+func journalFieldName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, key)
+	name = strings.TrimLeft(name, "_0123456789")
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+func journalPriority(level logrus.Level) syslog.Priority {
+	switch level {
+	case logrus.PanicLevel, logrus.FatalLevel:
+		return syslog.LOG_CRIT
+	case logrus.ErrorLevel:
+		return syslog.LOG_ERR
+	case logrus.WarnLevel:
+		return syslog.LOG_WARNING
+	case logrus.InfoLevel:
+		return syslog.LOG_INFO
+	}
+	return syslog.LOG_DEBUG
+}
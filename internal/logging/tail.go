@@ -0,0 +1,201 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"time"
+)
+
+// tailChunkSize is the size of the blocks read backwards by ReverseLines
+const tailChunkSize = 64 << 10
+
+// followInterval is how often Follow checks the log file for new lines
+const followInterval = 250 * time.Millisecond
+
+// Tail returns the last n lines of the file at path, reading it backwards from the end
+// so that the cost does not depend on the size of the file
+func Tail(path string, n int) ([]string, error) {
+	return TailFunc(path, n, nil)
+}
+
+// TailFunc returns the last n lines of the file at path for which keep returns true, in file order.
+// A nil keep keeps every line. Reading stops early when keep returns stop.
+func TailFunc(path string, n int, keep func(line string) (ok, stop bool)) ([]string, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+	var lines []string
+	err := ReverseLines(path, func(line string) bool {
+		ok, stop := true, false
+		if keep != nil {
+			ok, stop = keep(line)
+		}
+		if ok {
+			lines = append(lines, line)
+		}
+		return !stop && len(lines) < n
+	})
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+	return lines, err
+}
+
+// ReverseLines calls fn with every non-empty line of the file at path, from the last one,
+// until fn returns false
+func ReverseLines(path string, fn func(line string) bool) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	// rest holds the start of the last line read, whose beginning is in the previous chunk
+	var rest []byte
+	offset := info.Size()
+	for offset > 0 {
+		size := int64(tailChunkSize)
+		if size > offset {
+			size = offset
+		}
+		offset -= size
+		chunk := make([]byte, size, size+int64(len(rest)))
+		if _, err := file.ReadAt(chunk, offset); err != nil && err != io.EOF {
+			return err
+		}
+		data := append(chunk, rest...)
+
+		for {
+			i := bytes.LastIndexByte(data, '\n')
+			if i < 0 {
+				break
+			}
+			if line := data[i+1:]; len(line) > 0 && !fn(string(line)) {
+				return nil
+			}
+			data = data[:i]
+		}
+		rest = data
+	}
+	if len(rest) > 0 {
+		fn(string(rest))
+	}
+	return nil
+}
+
+// Follow calls fn with every line appended to the file at path until ctx is done, like tail -F:
+// when the file is rotated or truncated, it continues from the start of the new file.
+// Lines already in the file when Follow starts are skipped.
+func Follow(ctx context.Context, path string, fn func(line string)) error {
+	var file *os.File
+	defer func() {
+		if file != nil {
+			file.Close()
+		}
+	}()
+
+	var partial []byte
+	buf := make([]byte, 32<<10)
+	first := true
+	ticker := time.NewTicker(followInterval)
+	defer ticker.Stop()
+
+	for {
+		if file == nil {
+			if f, err := os.Open(path); err == nil {
+				file = f
+				if first {
+					// Only lines written from now on
+					file.Seek(0, io.SeekEnd)
+				}
+			}
+			first = false
+		}
+
+		if file != nil {
+			for {
+				n, err := file.Read(buf)
+				if n > 0 {
+					partial = append(partial, buf[:n]...)
+					for {
+						i := bytes.IndexByte(partial, '\n')
+						if i < 0 {
+							break
+						}
+						if i > 0 {
+							fn(string(partial[:i]))
+						}
+						partial = partial[i+1:]
+					}
+				}
+				if err != nil || n == 0 {
+					break
+				}
+			}
+
+			if replacedOrTruncated(file, path) {
+				file.Close()
+				file, partial = nil, nil
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// replacedOrTruncated reports whether path is no longer the open file or was truncated below the read offset
+func replacedOrTruncated(file *os.File, path string) bool {
+	current, err := os.Stat(path)
+	if err != nil {
+		// Rotated and not created again yet
+		return true
+	}
+	opened, err := file.Stat()
+	if err != nil || !os.SameFile(current, opened) {
+		return true
+	}
+	offset, err := file.Seek(0, io.SeekCurrent)
+	return err != nil || current.Size() < offset
+}
+
+// Recent returns the last n entries of the file at path that match filter, oldest first
+func Recent(path string, filter Filter, n int) ([]Entry, error) {
+	var entries []Entry
+	_, err := TailFunc(path, n, func(line string) (bool, bool) {
+		entry := ParseLine(line)
+		if !filter.Since.IsZero() && !entry.Time.IsZero() && entry.Time.Before(filter.Since) {
+			// Entries are in time order: the older ones cannot match either
+			return false, true
+		}
+		if !filter.Match(entry) {
+			return false, false
+		}
+		entries = append(entries, entry)
+		return true, false
+	})
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+	return entries, err
+}
+
+// FollowEntries calls fn with every entry matching filter appended to the file at path until ctx is done
+func FollowEntries(ctx context.Context, path string, filter Filter, fn func(Entry)) error {
+	return Follow(ctx, path, func(line string) {
+		if entry := ParseLine(line); filter.Match(entry) {
+			fn(entry)
+		}
+	})
+}
@@ -0,0 +1,97 @@
+package manifest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"goction/internal/config"
+)
+
+// FileName is the name of the manifest file inside a goction directory
+const FileName = "goction.json"
+
+// RedactedValue replaces secret values wherever they would be displayed or stored
+const RedactedValue = "[REDACTED]"
+
+// Manifest describes a goction: its metadata, the arguments it accepts, the secrets it needs
+// and the environment, timeout, resource limits and sandbox of its process
+type Manifest struct {
+	Description   string                    `json:"description,omitempty"`
+	Version       string                    `json:"version,omitempty"`
+	Args          []Arg                     `json:"args,omitempty"`
+	Secrets       []string                  `json:"secrets,omitempty"`
+	Env           []EnvVar                  `json:"env,omitempty"`
+	InheritEnv    []string                  `json:"inherit_env,omitempty"`
+	Timeout       int                       `json:"timeout,omitempty"`
+	Limits        *config.Limits            `json:"limits,omitempty"`
+	Sandbox       *config.Sandbox           `json:"sandbox,omitempty"`
+	Notifications []config.NotificationHook `json:"notifications,omitempty"`
+	// Disabled goctions are not executed
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// Arg declares a positional argument of a goction
+type Arg struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+	Secret      bool   `json:"secret,omitempty"`
+}
+
+// EnvVar declares an environment variable of the goction process, set to Value or to the value of the Secret
+type EnvVar struct {
+	Name   string `json:"name"`
+	Value  string `json:"value,omitempty"`
+	Secret string `json:"secret,omitempty"`
+}
+
+// Load reads the manifest of the goction stored in goctionDir.
+// A goction without a manifest gets an empty one.
+func Load(goctionDir string) (*Manifest, error) {
+	path := filepath.Join(goctionDir, FileName)
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return &Manifest{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open manifest: %w", err)
+	}
+	defer file.Close()
+
+	var m Manifest
+	if err := json.NewDecoder(file).Decode(&m); err != nil {
+		return nil, fmt.Errorf("failed to decode manifest %s: %w", path, err)
+	}
+
+	return &m, nil
+}
+
+// Save writes the manifest into goctionDir
+func (m *Manifest) Save(goctionDir string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(goctionDir, FileName), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+// RedactArgs returns a copy of args where arguments declared as secret are replaced by RedactedValue
+func (m *Manifest) RedactArgs(args []string) []string {
+	if args == nil {
+		return nil
+	}
+	redacted := make([]string, len(args))
+	copy(redacted, args)
+	for i, arg := range m.Args {
+		if arg.Secret && i < len(redacted) {
+			redacted[i] = RedactedValue
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,120 @@
+package notify
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Delivery statuses
+const (
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+// Delivery is the outcome of sending one notification
+type Delivery struct {
+	ID          string    `json:"id"`
+	ExecutionID string    `json:"execution_id"`
+	Goction     string    `json:"goction"`
+	URL         string    `json:"url"`
+	Events      []string  `json:"events"`
+	Status      string    `json:"status"`
+	StatusCode  int       `json:"status_code,omitempty"`
+	Attempts    int       `json:"attempts"`
+	Error       string    `json:"error,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	FinishedAt  time.Time `json:"finished_at"`
+}
+
+// DeliveryLog is an append-only JSON lines file of deliveries, shared by the CLI and the server
+type DeliveryLog struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewDeliveryLog creates a delivery log stored at path
+func NewDeliveryLog(path string) *DeliveryLog {
+	return &DeliveryLog{path: path}
+}
+
+// Append adds a delivery to the log
+func (l *DeliveryLog) Append(delivery Delivery) error {
+	data, err := json.Marshal(delivery)
+	if err != nil {
+		return fmt.Errorf("failed to encode delivery: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0775); err != nil {
+		return fmt.Errorf("failed to create delivery log directory: %w", err)
+	}
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0664)
+	if err != nil {
+		return fmt.Errorf("failed to open delivery log: %w", err)
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(data, '\n'))
+	return err
+}
+
+// DeliveryFilter selects deliveries from the log
+type DeliveryFilter struct {
+	Goction     string
+	ExecutionID string
+	Status      string
+	Limit       int
+}
+
+// List returns the most recent deliveries matching the filter, newest first
+func (l *DeliveryLog) List(filter DeliveryFilter) ([]Delivery, error) {
+	file, err := os.Open(l.path)
+	if os.IsNotExist(err) {
+		return []Delivery{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open delivery log: %w", err)
+	}
+	defer file.Close()
+
+	var deliveries []Delivery
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var delivery Delivery
+		if err := json.Unmarshal(scanner.Bytes(), &delivery); err != nil {
+			continue
+		}
+		if filter.Goction != "" && filter.Goction != delivery.Goction {
+			continue
+		}
+		if filter.ExecutionID != "" && filter.ExecutionID != delivery.ExecutionID {
+			continue
+		}
+		if filter.Status != "" && filter.Status != delivery.Status {
+			continue
+		}
+		deliveries = append(deliveries, delivery)
+		if filter.Limit > 0 && len(deliveries) > filter.Limit {
+			deliveries = deliveries[1:]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read delivery log: %w", err)
+	}
+
+	for i, j := 0, len(deliveries)-1; i < j; i, j = i+1, j-1 {
+		deliveries[i], deliveries[j] = deliveries[j], deliveries[i]
+	}
+	if deliveries == nil {
+		deliveries = []Delivery{}
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,281 @@
+package notify
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"goction/internal/config"
+	"goction/internal/logging"
+	"goction/internal/manifest"
+	"goction/internal/stats"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// Notification events
+const (
+	EventSuccess  = "success"
+	EventFailure  = "failure"
+	EventDuration = "duration"
+)
+
+// AllGoctions is the key of config.Notifications whose hooks apply to every goction
+const AllGoctions = "*"
+
+const (
+	defaultMaxRetries = 3
+	maxConcurrent     = 8
+)
+
+// SignatureHeader carries the HMAC-SHA256 signature of the timestamp and the body, as "sha256=<hex>", see Sign
+const SignatureHeader = "X-Goction-Signature"
+
+// TimestampHeader carries the Unix time at which a delivery was signed, so that endpoints can reject replays
+const TimestampHeader = "X-Goction-Timestamp"
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// Payload is the JSON body delivered to notification hooks
+type Payload struct {
+	DeliveryID string                `json:"delivery_id"`
+	Events     []string              `json:"events"`
+	Execution  stats.ExecutionRecord `json:"execution"`
+	SentAt     time.Time             `json:"sent_at"`
+}
+
+// Dispatcher delivers execution notifications to the hooks declared in the configuration
+// and in goction manifests. It implements runner.Observer.
+type Dispatcher struct {
+	config atomic.Pointer[config.Config]
+	log    *DeliveryLog
+	logger *logrus.Logger
+
+	wg        sync.WaitGroup
+	semaphore chan struct{}
+	backoff   time.Duration
+}
+
+// NewDispatcher creates a dispatcher logging deliveries to the configured notification log
+func NewDispatcher(cfg *config.Config, logger *logrus.Logger) *Dispatcher {
+	d := &Dispatcher{
+		log:       NewDeliveryLog(cfg.NotificationLogPath()),
+		logger:    logger,
+		semaphore: make(chan struct{}, maxConcurrent),
+		backoff:   time.Second,
+	}
+	d.config.Store(cfg)
+	return d
+}
+
+// SetConfig replaces the configuration the notification hooks are read from
+func (d *Dispatcher) SetConfig(cfg *config.Config) {
+	d.config.Store(cfg)
+}
+
+// Log returns the delivery log
+func (d *Dispatcher) Log() *DeliveryLog {
+	return d.log
+}
+
+// Wait blocks until every pending delivery has succeeded or exhausted its retries, or until timeout.
+// It reports whether every delivery finished.
+func (d *Dispatcher) Wait(timeout time.Duration) bool {
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// ExecutionStarted implements runner.Observer
+func (d *Dispatcher) ExecutionStarted(name string) {}
+
+// PluginLoadFailed implements runner.Observer
+func (d *Dispatcher) PluginLoadFailed(name string, err error) {}
+
+// ExecutionFinished implements runner.Observer by delivering the execution to every matching hook
+func (d *Dispatcher) ExecutionFinished(record stats.ExecutionRecord) {
+	for _, hook := range d.hooks(record.Goction) {
+		events, err := matchEvents(hook, record)
+		if err != nil {
+			d.logger.WithError(err).WithField(logging.FieldGoction, record.Goction).Error("Invalid notification hook")
+			continue
+		}
+		if len(events) == 0 {
+			continue
+		}
+
+		payload := Payload{
+			DeliveryID: uuid.New().String(),
+			Events:     events,
+			Execution:  record,
+		}
+		d.wg.Add(1)
+		go func(hook config.NotificationHook) {
+			defer d.wg.Done()
+			d.semaphore <- struct{}{}
+			defer func() { <-d.semaphore }()
+			d.deliver(hook, payload)
+		}(hook)
+	}
+}
+
+// hooks returns the hooks configured for every goction, for this goction and in its manifest
+func (d *Dispatcher) hooks(name string) []config.NotificationHook {
+	cfg := d.config.Load()
+	var hooks []config.NotificationHook
+	hooks = append(hooks, cfg.Notifications[AllGoctions]...)
+	hooks = append(hooks, cfg.Notifications[name]...)
+
+	m, err := manifest.Load(filepath.Join(cfg.GoctionsDir, name))
+	if err != nil {
+		d.logger.WithError(err).WithField(logging.FieldGoction, name).Warn("Failed to load manifest notifications")
+		return hooks
+	}
+	return append(hooks, m.Notifications...)
+}
+
+// matchEvents returns the events of the hook matched by the execution
+func matchEvents(hook config.NotificationHook, record stats.ExecutionRecord) ([]string, error) {
+	var events []string
+	for _, on := range hook.On {
+		switch on {
+		case EventSuccess:
+			if record.Status == stats.StatusSuccess {
+				events = append(events, on)
+			}
+		case EventFailure:
+			if record.Status != stats.StatusSuccess {
+				events = append(events, on)
+			}
+		case EventDuration:
+			threshold, err := time.ParseDuration(hook.DurationThreshold)
+			if err != nil || threshold <= 0 {
+				return nil, fmt.Errorf("hook %s: invalid duration_threshold %q", hook.URL, hook.DurationThreshold)
+			}
+			if record.Duration >= threshold {
+				events = append(events, on)
+			}
+		default:
+			return nil, fmt.Errorf("hook %s: unknown event %q (expected %s, %s or %s)", hook.URL, on, EventSuccess, EventFailure, EventDuration)
+		}
+	}
+	return events, nil
+}
+
+// deliver posts the payload, retrying with exponential backoff, and logs the outcome
+func (d *Dispatcher) deliver(hook config.NotificationHook, payload Payload) {
+	maxRetries := hook.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = defaultMaxRetries
+	}
+
+	delivery := Delivery{
+		ID:          payload.DeliveryID,
+		ExecutionID: payload.Execution.ID,
+		Goction:     payload.Execution.Goction,
+		URL:         hook.URL,
+		Events:      payload.Events,
+		CreatedAt:   time.Now(),
+	}
+
+	backoff := d.backoff
+	for attempt := 1; attempt <= maxRetries+1; attempt++ {
+		delivery.Attempts = attempt
+		statusCode, err := d.post(hook, payload)
+		delivery.StatusCode = statusCode
+		if err == nil {
+			delivery.Status = DeliveryDelivered
+			delivery.Error = ""
+			break
+		}
+
+		delivery.Status = DeliveryFailed
+		delivery.Error = err.Error()
+		if attempt <= maxRetries {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+	delivery.FinishedAt = time.Now()
+
+	entry := d.logger.WithFields(logrus.Fields{
+		logging.FieldGoction:     delivery.Goction,
+		logging.FieldExecutionID: delivery.ExecutionID,
+		"url":                    delivery.URL,
+		"attempts":               delivery.Attempts,
+	})
+	if delivery.Status == DeliveryDelivered {
+		entry.Info("Notification delivered")
+	} else {
+		entry.WithField("error", delivery.Error).Error("Notification delivery failed")
+	}
+
+	if err := d.log.Append(delivery); err != nil {
+		d.logger.WithError(err).Error("Failed to write notification delivery log")
+	}
+}
+
+func (d *Dispatcher) post(hook config.NotificationHook, payload Payload) (int, error) {
+	payload.SentAt = time.Now()
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "goction/"+config.GoctionVersion)
+	req.Header.Set("X-Goction-Delivery", payload.DeliveryID)
+	for name, value := range hook.Headers {
+		req.Header.Set(name, value)
+	}
+	if hook.Secret != "" {
+		timestamp := strconv.FormatInt(payload.SentAt.Unix(), 10)
+		req.Header.Set(TimestampHeader, timestamp)
+		req.Header.Set(SignatureHeader, Sign(hook.Secret, timestamp, body))
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint returned %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the signature sent in SignatureHeader: "sha256=" followed by the hex HMAC-SHA256
+// of the timestamp sent in TimestampHeader, a dot and the body
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
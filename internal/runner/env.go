@@ -0,0 +1,54 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"goction/internal/config"
+	"goction/internal/manifest"
+)
+
+// DefaultInheritedEnv are the server environment variables every goction process inherits
+var DefaultInheritedEnv = []string{"PATH", "LANG", "LC_ALL", "TZ"}
+
+// prepareWorkDir creates the scratch directory of a goction, used as its working directory
+func prepareWorkDir(cfg *config.Config, name string) (string, error) {
+	dir := filepath.Join(cfg.WorkPath(), name)
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return "", fmt.Errorf("failed to create working directory of %s: %w", name, err)
+	}
+	return dir, nil
+}
+
+// buildEnv returns the environment of a goction process, in increasing order of precedence:
+// the inherited server variables, the variables declared in the manifest and the goction runtime variables
+func buildEnv(m *manifest.Manifest, spec execution) []string {
+	vars := make(map[string]string)
+	for _, name := range append(append([]string(nil), DefaultInheritedEnv...), m.InheritEnv...) {
+		if value, ok := os.LookupEnv(name); ok {
+			vars[name] = value
+		}
+	}
+
+	for _, env := range m.Env {
+		if env.Secret != "" {
+			vars[env.Name] = spec.secrets[env.Secret]
+		} else {
+			vars[env.Name] = env.Value
+		}
+	}
+
+	vars["HOME"] = spec.workDir
+	vars["GOCTION_NAME"] = spec.name
+	vars["GOCTION_WORK_DIR"] = spec.workDir
+	vars["GOCTION_EXECUTION_ID"] = spec.id
+
+	env := make([]string, 0, len(vars))
+	for name, value := range vars {
+		env = append(env, name+"="+value)
+	}
+	sort.Strings(env)
+	return env
+}
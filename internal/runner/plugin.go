@@ -0,0 +1,110 @@
+package runner
+
+import (
+	"fmt"
+	"plugin"
+
+	"goction/internal/execlog"
+	"goction/pkg/goctionutil"
+)
+
+// SecretsSymbol is the variable of type map[string]string a goction plugin exports to receive
+// the secrets declared in its manifest
+const SecretsSymbol = "Secrets"
+
+// goctionPlugin is a loaded goction plugin
+type goctionPlugin struct {
+	run     LoggingGoctionFunc
+	secrets *map[string]string
+}
+
+// openPlugin opens the plugin at path and looks up the goction function and its secrets variable
+func openPlugin(path, name string) (*goctionPlugin, error) {
+	plug, err := plugin.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not open goction plugin: %w", err)
+	}
+
+	sym, err := plug.Lookup(goctionutil.SymbolName(name))
+	if err != nil {
+		return nil, fmt.Errorf("could not find goction symbol: %w", err)
+	}
+
+	var goction *goctionPlugin
+	switch run := sym.(type) {
+	case func(...string) (string, error):
+		goction = &goctionPlugin{run: func(log func(string), args ...string) (string, error) {
+			return run(args...)
+		}}
+	case func(func(string), ...string) (string, error):
+		goction = &goctionPlugin{run: run}
+	default:
+		return nil, fmt.Errorf("unexpected type from module symbol")
+	}
+
+	if sym, err := plug.Lookup(SecretsSymbol); err == nil {
+		var ok bool
+		if goction.secrets, ok = sym.(*map[string]string); !ok {
+			return nil, fmt.Errorf("%s must be declared as a map[string]string variable", SecretsSymbol)
+		}
+	}
+
+	return goction, nil
+}
+
+// provideSecrets assigns the secret values to the plugin's secrets variable.
+// The variable is global to the plugin, so it is only used by the child process of a single execution.
+func (g *goctionPlugin) provideSecrets(name string, values map[string]string) error {
+	if len(values) == 0 {
+		return nil
+	}
+	if g.secrets == nil {
+		return fmt.Errorf("goction %s declares secrets but does not export a %s map[string]string variable", name, SecretsSymbol)
+	}
+	*g.secrets = values
+	return nil
+}
+
+// call runs the goction, turning a panic into an error
+func (g *goctionPlugin) call(log func(string), args []string) (result string, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("goction panicked: %v", p)
+		}
+	}()
+	return g.run(log, args...)
+}
+
+// runPlugin executes a goction inside the current process.
+// Plugins are loaded once and cached. A plugin cannot be interrupted, so the timeout is not enforced: the execution
+// lasts, and stays in flight, until the goction returns. The goction shares the environment and working directory
+// of the process, and only the lines logged through the logger handed to it are captured, not its standard output.
+// Secrets are not provided: Run refuses goctions that declare some.
+func (r *Runner) runPlugin(spec execution, started func()) (string, error) {
+	goction, err := r.load(spec.name, spec.pluginPath)
+	if err != nil {
+		return "", err
+	}
+
+	started()
+
+	return goction.call(func(line string) {
+		spec.output.Line(execlog.StreamLog, line)
+	}, spec.args)
+}
+
+func (r *Runner) load(name, path string) (*goctionPlugin, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if goction, ok := r.cache[name]; ok {
+		return goction, nil
+	}
+
+	goction, err := openPlugin(path, name)
+	if err != nil {
+		return nil, err
+	}
+	r.cache[name] = goction
+	return goction, nil
+}
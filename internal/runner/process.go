@@ -0,0 +1,216 @@
+package runner
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"goction/internal/execlog"
+	"goction/internal/sandbox"
+)
+
+// ChildCommand is the hidden goction command that executes a goction in a child process.
+// It must be handled by main before the configuration is loaded, see ChildMain.
+const ChildCommand = "__run-goction"
+
+// childResponseFD is the file descriptor the child writes its messages to,
+// leaving stdout and stderr to the goction
+const childResponseFD = 3
+
+// childRequest is sent by the runner to the child on its standard input
+type childRequest struct {
+	Plugin  string            `json:"plugin"`
+	Goction string            `json:"goction"`
+	Args    []string          `json:"args"`
+	Secrets map[string]string `json:"secrets,omitempty"`
+	Sandbox sandbox.Spec      `json:"sandbox"`
+}
+
+// childMessage is written by the child once the plugin is loaded (or failed to load), for every line
+// the goction logs, then once the goction returned
+type childMessage struct {
+	Loaded    bool   `json:"loaded,omitempty"`
+	Log       string `json:"log,omitempty"`
+	LoadError string `json:"load_error,omitempty"`
+	Done      bool   `json:"done,omitempty"`
+	Result    string `json:"result,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// runProcess executes a goction in a child process running in the goction's working directory,
+// with the goction's environment only, its resource limits and sandbox.
+// A child exceeding the execution timeout is killed along with the processes it started.
+func (r *Runner) runProcess(spec execution, started func()) (string, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate the goction executable: %w", err)
+	}
+
+	cmd := exec.Command(executable, ChildCommand)
+	cleanup, err := sandbox.Configure(cmd, &spec.sandbox)
+	if err != nil {
+		return "", fmt.Errorf("failed to configure the goction sandbox: %w", err)
+	}
+	defer cleanup()
+
+	request, err := json.Marshal(childRequest{Plugin: spec.pluginPath, Goction: spec.name, Args: spec.args, Secrets: spec.secrets, Sandbox: spec.sandbox})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode goction request: %w", err)
+	}
+
+	responses, responseWriter, err := os.Pipe()
+	if err != nil {
+		return "", fmt.Errorf("failed to create goction pipe: %w", err)
+	}
+	defer responses.Close()
+
+	cmd.Dir = spec.workDir
+	cmd.Env = spec.env
+	cmd.Stdin = bytes.NewReader(request)
+	stdout, stderr := spec.output.Stream(execlog.StreamStdout), spec.output.Stream(execlog.StreamStderr)
+	defer stdout.Close()
+	defer stderr.Close()
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.ExtraFiles = []*os.File{responseWriter}
+	// Do not wait for the output of processes the goction left running in the background
+	cmd.WaitDelay = time.Second
+
+	err = cmd.Start()
+	responseWriter.Close()
+	if err != nil {
+		return "", fmt.Errorf("failed to start goction process: %w", err)
+	}
+
+	decoder := json.NewDecoder(responses)
+	var loaded childMessage
+	if err := decoder.Decode(&loaded); err != nil || !loaded.Loaded {
+		waitErr := cmd.Wait()
+		if loaded.LoadError != "" {
+			return "", errors.New(loaded.LoadError)
+		}
+		return "", fmt.Errorf("goction process exited before loading the plugin: %v", exitReason(waitErr, err))
+	}
+
+	started()
+
+	done := make(chan childMessage, 1)
+	waited := make(chan error, 1)
+	go func() {
+		var final childMessage
+		decodeErr := decoder.Decode(&final)
+		for decodeErr == nil && !final.Done {
+			spec.output.Line(execlog.StreamLog, final.Log)
+			final = childMessage{}
+			decodeErr = decoder.Decode(&final)
+		}
+		waitErr := cmd.Wait()
+		if decodeErr != nil || !final.Done {
+			final = childMessage{Done: true, Error: fmt.Sprintf("goction process exited: %v", exitReason(waitErr, decodeErr))}
+		}
+		done <- final
+		waited <- waitErr
+	}()
+
+	var timeout <-chan time.Time
+	if spec.timeout > 0 {
+		timer := time.NewTimer(spec.timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case final := <-done:
+		if final.Error != "" {
+			return final.Result, errors.New(final.Error)
+		}
+		return final.Result, nil
+	case <-timeout:
+		sandbox.Kill(cmd)
+		// Unblock the reader even if a process started by the goction still holds the pipe
+		responses.Close()
+		<-waited
+		return "", fmt.Errorf("%w after %s", ErrTimeout, spec.timeout)
+	}
+}
+
+// exitReason describes why a child stopped without sending a message
+func exitReason(waitErr, decodeErr error) error {
+	if waitErr != nil {
+		return waitErr
+	}
+	return decodeErr
+}
+
+// ChildMain runs the goction described on standard input and reports to the runner.
+// It returns the process exit code.
+func ChildMain() int {
+	out := os.NewFile(childResponseFD, "goction-response")
+	if out == nil {
+		fmt.Fprintln(os.Stderr, "goction: "+ChildCommand+" is reserved for internal use")
+		return 2
+	}
+	encoder := json.NewEncoder(out)
+
+	var req childRequest
+	if err := json.NewDecoder(os.Stdin).Decode(&req); err != nil {
+		encoder.Encode(childMessage{LoadError: fmt.Sprintf("invalid goction request: %v", err)})
+		return 1
+	}
+
+	if err := sandbox.Enter(req.Sandbox); err != nil {
+		encoder.Encode(childMessage{LoadError: err.Error()})
+		return 1
+	}
+	if req.Sandbox.Sandbox.Enabled() && !req.Sandbox.Entered {
+		// Run the goction in a new process that cannot undo the sandbox
+		req.Sandbox.Entered = true
+		request, err := json.Marshal(req)
+		if err == nil {
+			err = sandbox.Drop(req.Sandbox, request)
+		}
+		encoder.Encode(childMessage{LoadError: err.Error()})
+		return 1
+	}
+	goction, err := openPlugin(req.Plugin, req.Goction)
+	if err == nil {
+		err = goction.provideSecrets(req.Goction, req.Secrets)
+	}
+	if err == nil {
+		// The seccomp filter is installed last so that it only applies to the goction itself
+		err = sandbox.Restrict(req.Sandbox)
+	}
+	if err != nil {
+		encoder.Encode(childMessage{LoadError: err.Error()})
+		return 1
+	}
+	if err := encoder.Encode(childMessage{Loaded: true}); err != nil {
+		return 1
+	}
+
+	var mu sync.Mutex
+	log := func(line string) {
+		mu.Lock()
+		defer mu.Unlock()
+		encoder.Encode(childMessage{Log: line})
+	}
+	result, err := goction.call(log, req.Args)
+	// Lines logged by goroutines still running after the goction returned are dropped
+	mu.Lock()
+	final := childMessage{Done: true, Result: result}
+	if err != nil {
+		final.Error = err.Error()
+		if final.Error == "" {
+			final.Error = "goction failed"
+		}
+	}
+	if err := encoder.Encode(final); err != nil {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,334 @@
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"goction/internal/config"
+	"goction/internal/execlog"
+	"goction/internal/manifest"
+	"goction/internal/sandbox"
+	"goction/internal/secrets"
+	"goction/internal/stats"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// GoctionFunc is the signature every goction plugin exports
+type GoctionFunc func(...string) (string, error)
+
+// LoggingGoctionFunc is the signature of goctions receiving a logger: every line passed to log is stored
+// with the execution, see execlog
+type LoggingGoctionFunc func(log func(string), args ...string) (string, error)
+
+// ErrTimeout is returned when a goction process is killed for exceeding its execution timeout
+var ErrTimeout = errors.New("execution timed out")
+
+// ErrDisabled is returned, without recording an execution, for a goction disabled in its manifest
+var ErrDisabled = errors.New("goction is disabled")
+
+// LoadError reports that a goction could not be loaded, as opposed to a failed execution
+type LoadError struct {
+	Goction string
+	Err     error
+}
+
+func (e *LoadError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+// Request describes a single goction execution
+type Request struct {
+	Goction string
+	Args    []string
+	Caller  string
+	Trigger string
+	// Output, if set, receives a copy of the output of the goction as it is produced
+	Output io.Writer
+}
+
+// Observer is notified about the lifecycle of executions
+type Observer interface {
+	ExecutionStarted(name string)
+	ExecutionFinished(record stats.ExecutionRecord)
+	PluginLoadFailed(name string, err error)
+}
+
+// execution describes one run of a goction for the execution modes
+type execution struct {
+	id         string
+	name       string
+	pluginPath string
+	args       []string
+	secrets    map[string]string
+	// output stores what the goction prints and logs
+	output *execlog.Writer
+	// timeout, workDir, env and sandbox are only applied by the process mode
+	timeout time.Duration
+	workDir string
+	env     []string
+	sandbox sandbox.Spec
+}
+
+// Runner loads goction plugins, executes them and records their executions
+type Runner struct {
+	config    atomic.Pointer[config.Config]
+	logs      atomic.Pointer[execlog.Store]
+	stats     *stats.Manager
+	logger    *logrus.Logger
+	observers []Observer
+
+	mu    sync.Mutex
+	cache map[string]*goctionPlugin
+}
+
+// New creates a runner recording executions into statsManager
+func New(cfg *config.Config, statsManager *stats.Manager, logger *logrus.Logger) *Runner {
+	r := &Runner{
+		stats:  statsManager,
+		logger: logger,
+		cache:  make(map[string]*goctionPlugin),
+	}
+	r.SetConfig(cfg)
+	return r
+}
+
+// SetConfig replaces the configuration used by subsequent executions
+func (r *Runner) SetConfig(cfg *config.Config) {
+	r.config.Store(cfg)
+	r.logs.Store(openLogs(cfg))
+}
+
+func openLogs(cfg *config.Config) *execlog.Store {
+	return execlog.Open(cfg.ExecutionLogsPath(), int64(cfg.ExecutionLogs.MaxSizeKB)<<10, time.Duration(cfg.ExecutionLogs.RetentionDays)*24*time.Hour)
+}
+
+// Logs returns the store holding the output of executions
+func (r *Runner) Logs() *execlog.Store {
+	return r.logs.Load()
+}
+
+// AddObserver registers an observer notified about every execution
+func (r *Runner) AddObserver(o Observer) {
+	r.observers = append(r.observers, o)
+}
+
+// Run executes a goction and records the execution.
+// The returned error is a *LoadError if the goction could not be loaded, in which case nothing is recorded.
+func (r *Runner) Run(req Request) (stats.ExecutionRecord, error) {
+	cfg := r.config.Load()
+	goctionDir := filepath.Join(cfg.GoctionsDir, req.Goction)
+
+	m, err := manifest.Load(goctionDir)
+	if err != nil {
+		return stats.ExecutionRecord{}, r.loadFailed(req.Goction, err)
+	}
+	if m.Disabled {
+		return stats.ExecutionRecord{}, fmt.Errorf("%w: %s", ErrDisabled, req.Goction)
+	}
+
+	pluginPath := filepath.Join(goctionDir, req.Goction+".so")
+	if _, err := os.Stat(pluginPath); os.IsNotExist(err) {
+		return stats.ExecutionRecord{}, r.loadFailed(req.Goction, fmt.Errorf("goction plugin not found. Please run 'goction update %s' to build the plugin", req.Goction))
+	}
+
+	if cfg.PluginMode() {
+		if feature := processOnly(m); feature != "" {
+			return stats.ExecutionRecord{}, r.loadFailed(req.Goction, fmt.Errorf("%s declares %s, which requires \"execution_mode\": %q", req.Goction, feature, config.ExecutionModeProcess))
+		}
+	}
+
+	secretValues, err := r.resolveSecrets(cfg, m)
+	if err != nil {
+		return stats.ExecutionRecord{}, fmt.Errorf("failed to provide secrets to %s: %w", req.Goction, err)
+	}
+
+	spec := execution{
+		id:         uuid.New().String(),
+		name:       req.Goction,
+		pluginPath: pluginPath,
+		args:       req.Args,
+		secrets:    secretValues,
+		timeout:    time.Duration(cfg.ExecutionTimeout) * time.Second,
+	}
+	if m.Timeout > 0 {
+		spec.timeout = time.Duration(m.Timeout) * time.Second
+	}
+	if !cfg.PluginMode() {
+		if spec.workDir, err = prepareWorkDir(cfg, req.Goction); err != nil {
+			return stats.ExecutionRecord{}, r.loadFailed(req.Goction, err)
+		}
+		spec.env = buildEnv(m, spec)
+		spec.sandbox = sandboxSpec(cfg, m, spec.workDir)
+	}
+
+	logs := r.Logs()
+	redact := func(line string) string {
+		return secrets.Redact(line, secretValues, manifest.RedactedValue)
+	}
+	spec.output, err = logs.Create(spec.id, redact)
+	if err != nil {
+		// The output is not worth failing the execution for
+		r.logger.WithError(err).WithField("goction", req.Goction).Warn("Failed to store the execution output, running without it")
+		spec.output = execlog.Discard(redact)
+	}
+	defer spec.output.Close()
+	if req.Output != nil {
+		spec.output.Mirror(req.Output)
+	}
+
+	var start time.Time
+	started := func() {
+		for _, o := range r.observers {
+			o.ExecutionStarted(req.Goction)
+		}
+		start = time.Now()
+	}
+
+	var result string
+	if cfg.PluginMode() {
+		result, err = r.runPlugin(spec, started)
+	} else {
+		result, err = r.runProcess(spec, started)
+	}
+	if start.IsZero() {
+		// The goction failed before it started executing
+		logs.Remove(spec.id)
+		return stats.ExecutionRecord{}, r.loadFailed(req.Goction, err)
+	}
+	duration := time.Since(start)
+
+	// Secret values must not leak into results, errors or the recorded arguments
+	result = secrets.Redact(result, secretValues, manifest.RedactedValue)
+	args := m.RedactArgs(req.Args)
+	for i := range args {
+		args[i] = secrets.Redact(args[i], secretValues, manifest.RedactedValue)
+	}
+	if err != nil && !errors.Is(err, ErrTimeout) {
+		if redacted := secrets.Redact(err.Error(), secretValues, manifest.RedactedValue); redacted != err.Error() {
+			err = errors.New(redacted)
+		}
+	}
+
+	record := stats.ExecutionRecord{
+		ID:       spec.id,
+		Goction:  req.Goction,
+		Duration: duration,
+		Status:   stats.StatusSuccess,
+		Result:   result,
+		Args:     args,
+		Caller:   req.Caller,
+		Trigger:  req.Trigger,
+		Version:  buildVersion(m, pluginPath),
+	}
+	if err != nil {
+		record.Status = stats.StatusFailure
+		if errors.Is(err, ErrTimeout) {
+			record.Status = stats.StatusTimeout
+		}
+		record.Error = err.Error()
+	}
+
+	record = r.stats.RecordExecution(record)
+
+	for _, o := range r.observers {
+		o.ExecutionFinished(record)
+	}
+
+	return record, err
+}
+
+func (r *Runner) loadFailed(name string, err error) error {
+	for _, o := range r.observers {
+		o.PluginLoadFailed(name, err)
+	}
+	return &LoadError{Goction: name, Err: err}
+}
+
+// processOnly returns the first feature of the manifest that only the process execution mode provides, or an empty string.
+// Running such a goction in the plugin mode would silently ignore it.
+func processOnly(m *manifest.Manifest) string {
+	switch {
+	case m.Sandbox != nil && m.Sandbox.Enabled():
+		return "a sandbox"
+	case len(m.Secrets) > 0:
+		// The Secrets variable of a plugin is shared by its concurrent executions
+		return "secrets"
+	case len(m.Env) > 0 || len(m.InheritEnv) > 0:
+		return "environment variables"
+	case m.Limits != nil:
+		return "limits"
+	case m.Timeout > 0:
+		return "a timeout"
+	}
+	return ""
+}
+
+// resolveSecrets decrypts the secrets declared in the manifest, including those referenced by its environment
+func (r *Runner) resolveSecrets(cfg *config.Config, m *manifest.Manifest) (map[string]string, error) {
+	names := append([]string(nil), m.Secrets...)
+	for _, env := range m.Env {
+		if env.Secret != "" {
+			names = append(names, env.Secret)
+		}
+	}
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	key, err := secrets.LoadMasterKey(cfg.MasterKeyPath())
+	if err != nil {
+		return nil, err
+	}
+	store, err := secrets.Open(cfg.SecretsPath(), key)
+	if err != nil {
+		return nil, err
+	}
+	return store.Resolve(names)
+}
+
+// sandboxSpec merges the limits of the configuration and of the manifest.
+// Sandboxed goctions cannot see the configuration, secrets and statistics of goction.
+func sandboxSpec(cfg *config.Config, m *manifest.Manifest, workDir string) sandbox.Spec {
+	spec := sandbox.Spec{Limits: cfg.Limits, WorkDir: workDir}
+	if m.Limits != nil {
+		spec.Limits = spec.Limits.Merge(*m.Limits)
+	}
+	if m.Sandbox != nil && m.Sandbox.Enabled() {
+		spec.Sandbox = *m.Sandbox
+		for _, path := range []string{cfg.Path(), cfg.MasterKeyPath(), cfg.SecretsPath(), cfg.StatsFile, cfg.NotificationLogPath(), cfg.LogFile, cfg.ExecutionLogsPath()} {
+			// The child resolves paths from the goction working directory
+			if abs, err := filepath.Abs(path); err == nil {
+				spec.HiddenFiles = append(spec.HiddenFiles, abs)
+			}
+		}
+	}
+	return spec
+}
+
+// buildVersion identifies the goction build: the manifest version, if any, plus the plugin build time
+func buildVersion(m *manifest.Manifest, pluginPath string) string {
+	var build string
+	if info, err := os.Stat(pluginPath); err == nil {
+		build = info.ModTime().UTC().Format("20060102T150405Z")
+	}
+
+	switch {
+	case m.Version != "" && build != "":
+		return m.Version + "+" + build
+	case m.Version != "":
+		return m.Version
+	}
+	return build
+}
@@ -0,0 +1,6 @@
+//go:build linux && !amd64 && !arm64
+
+package sandbox
+
+// auditArch is unknown: the seccomp filter is not supported
+const auditArch = 0
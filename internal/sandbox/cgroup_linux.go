@@ -0,0 +1,150 @@
+package sandbox
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"golang.org/x/sys/unix"
+
+	"goction/internal/config"
+)
+
+const cgroupRoot = "/sys/fs/cgroup"
+
+// serverCgroup is the leaf cgroup the server moves itself into, since cgroup v2 only allows
+// enabling controllers for child cgroups of a cgroup without processes
+const serverCgroup = "goction-server"
+
+type cgroup struct {
+	path string
+	fd   int
+}
+
+// newCgroup creates a cgroup v2 enforcing the memory and process limits of one execution.
+// It fails when cgroups v2 are not available or not delegated to the goction user.
+func newCgroup(limits config.Limits) (*cgroup, error) {
+	parent, err := delegatedCgroup()
+	if err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(parent, "exec-"+uuid.New().String())
+	if err := os.Mkdir(path, 0755); err != nil {
+		return nil, err
+	}
+	group := &cgroup{path: path, fd: -1}
+
+	settings := map[string]string{}
+	if limits.MemoryMB > 0 {
+		settings["memory.max"] = strconv.FormatInt(int64(limits.MemoryMB)<<20, 10)
+		settings["memory.swap.max"] = "0"
+	}
+	if limits.Processes > 0 {
+		settings["pids.max"] = strconv.Itoa(limits.Processes)
+	}
+	for file, value := range settings {
+		if err := os.WriteFile(filepath.Join(path, file), []byte(value), 0644); err != nil && file != "memory.swap.max" {
+			group.remove()
+			return nil, fmt.Errorf("failed to set %s: %w", file, err)
+		}
+	}
+
+	group.fd, err = unix.Open(path, unix.O_DIRECTORY|unix.O_RDONLY|unix.O_CLOEXEC, 0)
+	if err != nil {
+		group.remove()
+		return nil, err
+	}
+	return group, nil
+}
+
+func (g *cgroup) remove() {
+	if g.fd >= 0 {
+		unix.Close(g.fd)
+	}
+	os.Remove(g.path)
+}
+
+// delegatedCgroup returns the cgroup under which execution cgroups are created,
+// enabling the memory and pids controllers for it if needed
+func delegatedCgroup() (string, error) {
+	current, err := currentCgroup()
+	if err != nil {
+		return "", err
+	}
+	dir := filepath.Join(cgroupRoot, current)
+	if filepath.Base(dir) == serverCgroup {
+		dir = filepath.Dir(dir)
+	}
+
+	controllers, err := os.ReadFile(filepath.Join(dir, "cgroup.controllers"))
+	if err != nil {
+		return "", err
+	}
+	for _, controller := range []string{"memory", "pids"} {
+		if !containsWord(string(controllers), controller) {
+			return "", fmt.Errorf("cgroup controller %s is not available", controller)
+		}
+	}
+
+	subtree, err := os.ReadFile(filepath.Join(dir, "cgroup.subtree_control"))
+	if err != nil {
+		return "", err
+	}
+	if containsWord(string(subtree), "memory") && containsWord(string(subtree), "pids") {
+		return dir, nil
+	}
+
+	// Controllers can only be enabled once the cgroup has no process left: move the server into a leaf cgroup
+	procs, err := os.ReadFile(filepath.Join(dir, "cgroup.procs"))
+	if err != nil {
+		return "", err
+	}
+	if pids := strings.Fields(string(procs)); len(pids) > 0 {
+		if len(pids) != 1 || pids[0] != strconv.Itoa(os.Getpid()) {
+			return "", errors.New("the goction cgroup is shared with other processes")
+		}
+		leaf := filepath.Join(dir, serverCgroup)
+		if err := os.Mkdir(leaf, 0755); err != nil && !os.IsExist(err) {
+			return "", err
+		}
+		if err := os.WriteFile(filepath.Join(leaf, "cgroup.procs"), []byte(strconv.Itoa(os.Getpid())), 0644); err != nil {
+			return "", err
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "cgroup.subtree_control"), []byte("+memory +pids"), 0644); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// currentCgroup returns the cgroup v2 path of the current process
+func currentCgroup() (string, error) {
+	f, err := os.Open("/proc/self/cgroup")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if path, ok := strings.CutPrefix(scanner.Text(), "0::"); ok {
+			return path, nil
+		}
+	}
+	return "", errors.New("cgroups v2 are not available")
+}
+
+func containsWord(s, word string) bool {
+	for _, field := range strings.Fields(s) {
+		if field == word {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,30 @@
+// Package sandbox applies resource limits and isolation to goction child processes.
+//
+// The runner calls Configure on the command before starting the child. The child calls Enter,
+// then, when the sandbox is enabled, Drop to execute itself again without privileges, and
+// Restrict once the goction plugin is loaded.
+package sandbox
+
+import "goction/internal/config"
+
+// Spec describes the limits and isolation of one goction process. It is sent to the child process.
+type Spec struct {
+	Limits  config.Limits  `json:"limits"`
+	Sandbox config.Sandbox `json:"sandbox"`
+	// WorkDir stays writable when the filesystem is read-only
+	WorkDir string `json:"work_dir"`
+	// HiddenFiles are goction's own files (configuration, secrets, statistics) hidden from sandboxed goctions
+	HiddenFiles []string `json:"hidden_files,omitempty"`
+	// Cgroup is set by Configure when the memory and process limits are enforced by a cgroup,
+	// in which case Enter does not fall back to rlimits for them
+	Cgroup bool `json:"cgroup,omitempty"`
+	// CgroupError is why Configure could not create a cgroup for the limits
+	CgroupError string `json:"cgroup_error,omitempty"`
+	// UserNamespace is set by Configure when the child runs as root of a new user namespace
+	UserNamespace bool `json:"user_namespace,omitempty"`
+	// Entered is set by Drop for the child it executes, whose sandbox is already set up
+	Entered bool `json:"entered,omitempty"`
+}
+
+// UnprivilegedID is the user and group ID sandboxed goctions run as when goction runs as root
+const UnprivilegedID = 65534
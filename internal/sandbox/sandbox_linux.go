@@ -0,0 +1,304 @@
+package sandbox
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// Configure prepares cmd to start the child process described by spec: its own process group,
+// the namespaces of a sandboxed goction and, when available, a cgroup enforcing the memory and process limits.
+// The returned cleanup function must be called once the child has exited.
+func Configure(cmd *exec.Cmd, spec *Spec) (func(), error) {
+	attr := &syscall.SysProcAttr{Setpgid: true}
+
+	if spec.Sandbox.Enabled() {
+		attr.Cloneflags = syscall.CLONE_NEWNS | syscall.CLONE_NEWPID | syscall.CLONE_NEWIPC | syscall.CLONE_NEWUTS
+		if spec.Sandbox.IsolateNetwork {
+			attr.Cloneflags |= syscall.CLONE_NEWNET
+		}
+		if os.Geteuid() != 0 {
+			// Without privileges, mounts are only possible as root of a new user namespace
+			attr.Cloneflags |= syscall.CLONE_NEWUSER
+			attr.UidMappings = []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Geteuid(), Size: 1}}
+			attr.GidMappings = []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getegid(), Size: 1}}
+			attr.GidMappingsEnableSetgroups = false
+			spec.UserNamespace = true
+		} else if spec.WorkDir != "" {
+			// Drop switches the goction to UnprivilegedID, which must be able to write to its working directory
+			if err := chownTree(spec.WorkDir, UnprivilegedID); err != nil {
+				return nil, fmt.Errorf("failed to hand %s over to the sandbox user: %w", spec.WorkDir, err)
+			}
+		}
+	}
+	cmd.SysProcAttr = attr
+
+	cleanup := func() {}
+	if spec.Limits.MemoryMB > 0 || spec.Limits.Processes > 0 {
+		group, err := newCgroup(spec.Limits)
+		if err != nil {
+			spec.CgroupError = err.Error()
+		} else {
+			attr.UseCgroupFD = true
+			attr.CgroupFD = group.fd
+			spec.Cgroup = true
+			cleanup = group.remove
+		}
+	}
+	return cleanup, nil
+}
+
+// chownTree hands dir and everything it contains over to id, without following symbolic links
+func chownTree(dir string, id int) error {
+	return filepath.WalkDir(dir, func(path string, _ fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		return os.Lchown(path, id, id)
+	})
+}
+
+// Kill kills the child process and every process it started
+func Kill(cmd *exec.Cmd) {
+	if cmd.Process == nil {
+		return
+	}
+	if err := syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL); err != nil {
+		cmd.Process.Kill()
+	}
+}
+
+// Enter applies the resource limits and the filesystem view. It runs in the child before the plugin is loaded.
+// Without a cgroup, the memory limit falls back to RLIMIT_DATA and the process limit is not enforced:
+// RLIMIT_NPROC counts every thread of the user, including those of the server. Both are reported on stderr.
+func Enter(spec Spec) error {
+	limits := spec.Limits
+	if limits.MemoryMB > 0 {
+		// Make the garbage collector work harder before reaching the hard limit
+		debug.SetMemoryLimit(int64(uint64(limits.MemoryMB) << 20 / 10 * 9))
+	}
+	if spec.Entered {
+		// The limits and the filesystem were set up before Drop executed this process
+		return nil
+	}
+
+	if limits.CPUSeconds > 0 {
+		// SIGXCPU at the soft limit, SIGKILL one second later
+		if err := setrlimit(unix.RLIMIT_CPU, uint64(limits.CPUSeconds), uint64(limits.CPUSeconds)+1); err != nil {
+			return fmt.Errorf("failed to limit CPU time: %w", err)
+		}
+	}
+	if limits.OpenFiles > 0 {
+		if err := setrlimit(unix.RLIMIT_NOFILE, uint64(limits.OpenFiles), uint64(limits.OpenFiles)); err != nil {
+			return fmt.Errorf("failed to limit open files: %w", err)
+		}
+	}
+	if limits.MemoryMB > 0 && !spec.Cgroup {
+		bytes := uint64(limits.MemoryMB) << 20
+		if err := setrlimit(unix.RLIMIT_DATA, bytes, bytes); err != nil {
+			return fmt.Errorf("failed to limit memory: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "goction: limits.memory_mb is enforced with RLIMIT_DATA, no cgroup could be created: %s\n", spec.CgroupError)
+	}
+	if limits.Processes > 0 && !spec.Cgroup {
+		fmt.Fprintf(os.Stderr, "goction: limits.processes is not enforced, no cgroup could be created: %s\n", spec.CgroupError)
+	}
+
+	if spec.Sandbox.Enabled() {
+		if err := setupFilesystem(spec); err != nil {
+			return fmt.Errorf("failed to set up the sandbox filesystem: %w", err)
+		}
+	}
+	return nil
+}
+
+// Drop executes the child again without the privileges it kept to set up the sandbox, with stdin
+// as its standard input. The new process runs as UnprivilegedID or, in a user namespace, as its root
+// with an empty capability bounding set, so it cannot undo the mounts. Drop only returns if it fails.
+func Drop(spec Spec, stdin []byte) error {
+	// Capabilities are a per-thread attribute, and execve keeps those of the calling thread
+	runtime.LockOSThread()
+
+	fd, err := unix.MemfdCreate("goction-request", 0)
+	if err != nil {
+		return fmt.Errorf("failed to pass the goction request: %w", err)
+	}
+	request := os.NewFile(uintptr(fd), "goction-request")
+	defer request.Close()
+	if _, err := request.Write(stdin); err != nil {
+		return fmt.Errorf("failed to pass the goction request: %w", err)
+	}
+	if _, err := request.Seek(0, 0); err != nil {
+		return fmt.Errorf("failed to pass the goction request: %w", err)
+	}
+	if err := unix.Dup3(int(request.Fd()), 0, 0); err != nil {
+		return fmt.Errorf("failed to pass the goction request: %w", err)
+	}
+
+	for capability := 0; ; capability++ {
+		err := unix.Prctl(unix.PR_CAPBSET_DROP, uintptr(capability), 0, 0, 0)
+		if errors.Is(err, unix.EINVAL) {
+			// Past the last capability known to the kernel
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to drop capability %d: %w", capability, err)
+		}
+	}
+	if err := unix.Prctl(unix.PR_CAP_AMBIENT, unix.PR_CAP_AMBIENT_CLEAR_ALL, 0, 0, 0); err != nil {
+		return fmt.Errorf("failed to clear ambient capabilities: %w", err)
+	}
+	if !spec.UserNamespace {
+		// Applied to every thread; the permitted capabilities are cleared when leaving root
+		if err := syscall.Setgroups([]int{}); err != nil {
+			return fmt.Errorf("failed to drop supplementary groups: %w", err)
+		}
+		if err := syscall.Setresgid(UnprivilegedID, UnprivilegedID, UnprivilegedID); err != nil {
+			return fmt.Errorf("failed to switch to group %d: %w", UnprivilegedID, err)
+		}
+		if err := syscall.Setresuid(UnprivilegedID, UnprivilegedID, UnprivilegedID); err != nil {
+			return fmt.Errorf("failed to switch to user %d: %w", UnprivilegedID, err)
+		}
+	}
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("failed to set no_new_privs: %w", err)
+	}
+
+	if err := unix.Exec("/proc/self/exe", os.Args, os.Environ()); err != nil {
+		return fmt.Errorf("failed to execute the sandboxed goction process: %w", err)
+	}
+	return nil
+}
+
+// Restrict installs the seccomp filter of sandboxed goctions. It runs in the child once the plugin is loaded.
+func Restrict(spec Spec) error {
+	if !spec.Sandbox.Enabled() {
+		return nil
+	}
+	if err := installSeccomp(); err != nil {
+		return fmt.Errorf("failed to install the seccomp filter: %w", err)
+	}
+	return nil
+}
+
+func setrlimit(resource int, soft, hard uint64) error {
+	return unix.Setrlimit(resource, &unix.Rlimit{Cur: soft, Max: hard})
+}
+
+// setupFilesystem moves the child into its own copy of the mount tree, makes the requested paths read-only
+// and hides goction's own files. Mounts only affect the mount namespace of the child.
+func setupFilesystem(spec Spec) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	if err := unix.Mount("", "/", "", unix.MS_REC|unix.MS_PRIVATE, ""); err != nil {
+		return fmt.Errorf("failed to make mounts private: %w", err)
+	}
+	if err := pivotRoot(); err != nil {
+		return err
+	}
+	// The inherited /proc shows the host processes, whose root directories give access to the hidden files
+	if err := unix.Mount("proc", "/proc", "proc", unix.MS_NOSUID|unix.MS_NODEV|unix.MS_NOEXEC, ""); err != nil {
+		return fmt.Errorf("failed to mount /proc: %w", err)
+	}
+
+	readOnly := spec.Sandbox.ReadOnlyPaths
+	if spec.Sandbox.ReadOnly {
+		readOnly = append([]string{"/"}, readOnly...)
+	}
+	for _, path := range readOnly {
+		if err := bindReadOnly(resolve(cwd, path)); err != nil {
+			return err
+		}
+	}
+	if spec.Sandbox.ReadOnly && spec.WorkDir != "" {
+		// A bind mount inherits the read-only flag of the mount it comes from
+		err := unix.Mount(spec.WorkDir, spec.WorkDir, "", unix.MS_BIND, "")
+		if err == nil {
+			err = unix.Mount("", spec.WorkDir, "", unix.MS_BIND|unix.MS_REMOUNT, "")
+		}
+		if err != nil {
+			return fmt.Errorf("failed to keep %s writable: %w", spec.WorkDir, err)
+		}
+	}
+
+	for _, path := range append(append([]string(nil), spec.HiddenFiles...), spec.Sandbox.HidePaths...) {
+		if err := hide(resolve(cwd, path)); err != nil {
+			return err
+		}
+	}
+	// Enter the working directory again, through the new mounts
+	return unix.Chdir(cwd)
+}
+
+// resolve returns path relative to dir if it is not absolute
+func resolve(dir, path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+// pivotRoot makes a copy of the mount tree the root of the child and detaches the original one,
+// so that only the restricted view stays reachable
+func pivotRoot() error {
+	// The new root must be a mount point other than the current root
+	newRoot := os.TempDir()
+	if err := unix.Mount("/", newRoot, "", unix.MS_BIND|unix.MS_REC, ""); err != nil {
+		return fmt.Errorf("failed to bind the root filesystem: %w", err)
+	}
+	if err := unix.Chdir(newRoot); err != nil {
+		return err
+	}
+	// The old root is stacked over the new one, and detached right away
+	if err := unix.PivotRoot(".", "."); err != nil {
+		return fmt.Errorf("failed to pivot the root filesystem: %w", err)
+	}
+	if err := unix.Unmount(".", unix.MNT_DETACH); err != nil {
+		return fmt.Errorf("failed to detach the original root filesystem: %w", err)
+	}
+	return nil
+}
+
+func bindReadOnly(path string) error {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+	if err := unix.Mount(path, path, "", unix.MS_BIND|unix.MS_REC, ""); err != nil {
+		return fmt.Errorf("failed to bind %s: %w", path, err)
+	}
+	if err := unix.Mount("", path, "", unix.MS_BIND|unix.MS_REMOUNT|unix.MS_RDONLY, ""); err != nil {
+		return fmt.Errorf("failed to make %s read-only: %w", path, err)
+	}
+	return nil
+}
+
+// hide mounts an empty read-only tmpfs over a directory, or /dev/null over a file
+func hide(path string) error {
+	info, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if info.IsDir() {
+		err = unix.Mount("tmpfs", path, "tmpfs", unix.MS_RDONLY|unix.MS_NOSUID|unix.MS_NODEV|unix.MS_NOEXEC, "size=0")
+	} else {
+		err = unix.Mount("/dev/null", path, "", unix.MS_BIND, "")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to hide %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+//go:build !linux
+
+package sandbox
+
+import (
+	"errors"
+	"os/exec"
+)
+
+var errUnsupported = errors.New("sandboxing is only supported on Linux")
+
+// Configure prepares cmd to start the child process described by spec.
+// Resource limits are not applied outside Linux.
+func Configure(cmd *exec.Cmd, spec *Spec) (func(), error) {
+	if spec.Sandbox.Enabled() {
+		return nil, errUnsupported
+	}
+	return func() {}, nil
+}
+
+// Kill kills the child process
+func Kill(cmd *exec.Cmd) {
+	if cmd.Process != nil {
+		cmd.Process.Kill()
+	}
+}
+
+// Enter is a no-op outside Linux
+func Enter(spec Spec) error {
+	return nil
+}
+
+// Drop is never called outside Linux, Configure refuses sandboxed goctions
+func Drop(spec Spec, stdin []byte) error {
+	return errUnsupported
+}
+
+// Restrict is a no-op outside Linux, Configure refuses sandboxed goctions
+func Restrict(spec Spec) error {
+	return nil
+}
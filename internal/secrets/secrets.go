@@ -0,0 +1,316 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MasterKeyEnv is the environment variable holding the master key. It takes precedence over the key file.
+const MasterKeyEnv = "GOCTION_MASTER_KEY"
+
+// keySize is the size of the AES-256 master key
+const keySize = 32
+
+// ErrNotFound is returned when a secret does not exist
+var ErrNotFound = errors.New("secret not found")
+
+var namePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// Info describes a stored secret without its value
+type Info struct {
+	Name      string    `json:"name"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// entry is a secret encrypted with AES-256-GCM, authenticated with its name
+type entry struct {
+	Nonce     string    `json:"nonce"`
+	Value     string    `json:"value"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type file struct {
+	Version int              `json:"version"`
+	Secrets map[string]entry `json:"secrets"`
+}
+
+// Store is an encrypted file of named secrets
+type Store struct {
+	path string
+	aead cipher.AEAD
+
+	mu sync.Mutex
+}
+
+// Open returns the store kept in path, encrypted with key. The file is created on the first Set.
+func Open(path string, key []byte) (*Store, error) {
+	if len(key) != keySize {
+		return nil, fmt.Errorf("master key must be %d bytes, got %d", keySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return &Store{path: path, aead: aead}, nil
+}
+
+// LoadMasterKey returns the master key from $GOCTION_MASTER_KEY or, if unset, from keyFile.
+// The key is 32 bytes encoded in base64 or hex; any other value is used as a passphrase hashed with SHA-256.
+func LoadMasterKey(keyFile string) ([]byte, error) {
+	if value := os.Getenv(MasterKeyEnv); value != "" {
+		return parseKey(value), nil
+	}
+
+	data, err := os.ReadFile(keyFile)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no master key: set %s or create %s", MasterKeyEnv, keyFile)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read master key: %w", err)
+	}
+	return parseKey(string(data)), nil
+}
+
+// LoadOrCreateMasterKey is LoadMasterKey, but generates a random key file if there is no key yet
+func LoadOrCreateMasterKey(keyFile string) ([]byte, bool, error) {
+	key, err := LoadMasterKey(keyFile)
+	if err == nil {
+		return key, false, nil
+	}
+	if _, statErr := os.Stat(keyFile); !os.IsNotExist(statErr) {
+		return nil, false, err
+	}
+
+	key = make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, false, fmt.Errorf("failed to generate master key: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(keyFile), 0750); err != nil {
+		return nil, false, fmt.Errorf("failed to create master key directory: %w", err)
+	}
+	encoded := base64.StdEncoding.EncodeToString(key) + "\n"
+	if err := os.WriteFile(keyFile, []byte(encoded), 0600); err != nil {
+		return nil, false, fmt.Errorf("failed to write master key: %w", err)
+	}
+	return key, true, nil
+}
+
+func parseKey(value string) []byte {
+	value = strings.TrimSpace(value)
+	if key, err := base64.StdEncoding.DecodeString(value); err == nil && len(key) == keySize {
+		return key
+	}
+	if key, err := hex.DecodeString(value); err == nil && len(key) == keySize {
+		return key
+	}
+	sum := sha256.Sum256([]byte(value))
+	return sum[:]
+}
+
+// ValidateName checks that name can be used as a secret name
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid secret name %q: use letters, digits, '_', '.' and '-', starting with a letter or '_'", name)
+	}
+	return nil
+}
+
+// Set encrypts and stores a secret, replacing any previous value
+func (s *Store) Set(name, value string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	f.Secrets[name] = entry{
+		Nonce:     base64.StdEncoding.EncodeToString(nonce),
+		Value:     base64.StdEncoding.EncodeToString(s.aead.Seal(nil, nonce, []byte(value), []byte(name))),
+		UpdatedAt: time.Now(),
+	}
+	return s.write(f)
+}
+
+// Get decrypts a secret. It returns ErrNotFound if the secret does not exist.
+func (s *Store) Get(name string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := s.read()
+	if err != nil {
+		return "", err
+	}
+	return s.decrypt(f, name)
+}
+
+// Resolve decrypts the given secrets, failing if any of them is missing
+func (s *Store) Resolve(names []string) (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(names))
+	for _, name := range names {
+		value, err := s.decrypt(f, name)
+		if err != nil {
+			return nil, err
+		}
+		values[name] = value
+	}
+	return values, nil
+}
+
+// List returns the stored secrets sorted by name
+func (s *Store) List() ([]Info, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]Info, 0, len(f.Secrets))
+	for name, e := range f.Secrets {
+		infos = append(infos, Info{Name: name, UpdatedAt: e.UpdatedAt})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos, nil
+}
+
+// Delete removes a secret. It returns ErrNotFound if the secret does not exist.
+func (s *Store) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := s.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := f.Secrets[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	delete(f.Secrets, name)
+	return s.write(f)
+}
+
+func (s *Store) decrypt(f *file, name string) (string, error) {
+	e, ok := f.Secrets[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	nonce, err := base64.StdEncoding.DecodeString(e.Nonce)
+	if err != nil {
+		return "", fmt.Errorf("secret %s is corrupted: %w", name, err)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(e.Value)
+	if err != nil {
+		return "", fmt.Errorf("secret %s is corrupted: %w", name, err)
+	}
+	plaintext, err := s.aead.Open(nil, nonce, ciphertext, []byte(name))
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt secret %s: wrong master key or corrupted store", name)
+	}
+	return string(plaintext), nil
+}
+
+func (s *Store) read() (*file, error) {
+	f := &file{Version: 1, Secrets: make(map[string]entry)}
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secrets store: %w", err)
+	}
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("failed to decode secrets store: %w", err)
+	}
+	if f.Secrets == nil {
+		f.Secrets = make(map[string]entry)
+	}
+	return f, nil
+}
+
+// write replaces the store file atomically, readable by its owner only
+func (s *Store) write(f *file) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode secrets store: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0750); err != nil {
+		return fmt.Errorf("failed to create secrets directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".secrets-*")
+	if err != nil {
+		return fmt.Errorf("failed to write secrets store: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(data, '\n')); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write secrets store: %w", err)
+	}
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write secrets store: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write secrets store: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to write secrets store: %w", err)
+	}
+	return nil
+}
+
+// Redact replaces every occurrence of the secret values in s with replacement
+func Redact(s string, values map[string]string, replacement string) string {
+	// Replace longer values first so that a value containing another one is fully redacted
+	sorted := make([]string, 0, len(values))
+	for _, value := range values {
+		if value != "" {
+			sorted = append(sorted, value)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+
+	for _, value := range sorted {
+		s = strings.ReplaceAll(s, value, replacement)
+	}
+	return s
+}
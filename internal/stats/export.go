@@ -0,0 +1,373 @@
+package stats
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Export formats
+const (
+	FormatCSV      = "csv"
+	FormatJSONL    = "jsonl"
+	FormatColumnar = "columnar"
+)
+
+// Export kinds
+const (
+	KindHistory = "history"
+	KindStats   = "stats"
+)
+
+// ExportFormats lists the supported export formats
+var ExportFormats = []string{FormatCSV, FormatJSONL, FormatColumnar}
+
+// ExportRow is the flat representation of an execution record used by every export format
+type ExportRow struct {
+	ID         string   `json:"id"`
+	Goction    string   `json:"goction"`
+	Timestamp  string   `json:"timestamp"`
+	DurationMs float64  `json:"duration_ms"`
+	Status     string   `json:"status"`
+	Result     string   `json:"result"`
+	Error      string   `json:"error"`
+	Args       []string `json:"args"`
+	Caller     string   `json:"caller"`
+	Trigger    string   `json:"trigger"`
+	Host       string   `json:"host"`
+	Version    string   `json:"version"`
+}
+
+// StatsRow is the per-goction aggregate exported by the stats kind
+type StatsRow struct {
+	Goction         string  `json:"goction"`
+	TotalCalls      int     `json:"total_calls"`
+	SuccessfulCalls int     `json:"successful_calls"`
+	FailedCalls     int     `json:"failed_calls"`
+	SuccessRate     float64 `json:"success_rate"`
+	TotalDurationMs float64 `json:"total_duration_ms"`
+	AvgDurationMs   float64 `json:"avg_duration_ms"`
+	FirstExecuted   string  `json:"first_executed"`
+	LastExecuted    string  `json:"last_executed"`
+}
+
+var (
+	historyColumns = []string{"id", "goction", "timestamp", "duration_ms", "status", "result", "error", "args", "caller", "trigger", "host", "version"}
+	statsColumns   = []string{"goction", "total_calls", "successful_calls", "failed_calls", "success_rate", "total_duration_ms", "avg_duration_ms", "first_executed", "last_executed"}
+)
+
+// NewExportRow flattens an execution record
+func NewExportRow(record ExecutionRecord) ExportRow {
+	return ExportRow{
+		ID:         record.ID,
+		Goction:    record.Goction,
+		Timestamp:  record.Timestamp.Format(time.RFC3339Nano),
+		DurationMs: float64(record.Duration) / float64(time.Millisecond),
+		Status:     record.Status,
+		Result:     record.Result,
+		Error:      record.Error,
+		Args:       record.Args,
+		Caller:     record.Caller,
+		Trigger:    record.Trigger,
+		Host:       record.Host,
+		Version:    record.Version,
+	}
+}
+
+// Record converts an export row back into an execution record
+func (r ExportRow) Record() (ExecutionRecord, error) {
+	timestamp, err := time.Parse(time.RFC3339Nano, r.Timestamp)
+	if err != nil {
+		return ExecutionRecord{}, fmt.Errorf("invalid timestamp %q: %w", r.Timestamp, err)
+	}
+	return ExecutionRecord{
+		ID:        r.ID,
+		Goction:   r.Goction,
+		Timestamp: timestamp,
+		Duration:  time.Duration(r.DurationMs * float64(time.Millisecond)),
+		Status:    r.Status,
+		Result:    r.Result,
+		Error:     r.Error,
+		Args:      r.Args,
+		Caller:    r.Caller,
+		Trigger:   r.Trigger,
+		Host:      r.Host,
+		Version:   r.Version,
+	}, nil
+}
+
+func (r ExportRow) values() []any {
+	return []any{r.ID, r.Goction, r.Timestamp, r.DurationMs, r.Status, r.Result, r.Error, r.Args, r.Caller, r.Trigger, r.Host, r.Version}
+}
+
+func (r StatsRow) values() []any {
+	return []any{r.Goction, r.TotalCalls, r.SuccessfulCalls, r.FailedCalls, r.SuccessRate, r.TotalDurationMs, r.AvgDurationMs, r.FirstExecuted, r.LastExecuted}
+}
+
+// ContentType returns the MIME type of an export format
+func ContentType(format string) string {
+	switch format {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatJSONL:
+		return "application/x-ndjson"
+	default:
+		return "application/json"
+	}
+}
+
+// FileExtension returns the file extension of an export format
+func FileExtension(format string) string {
+	if format == FormatColumnar {
+		return "json"
+	}
+	return format
+}
+
+// EachRecord calls fn for every record matching the query filters, ignoring its limit and cursor.
+// Records are visited goction by goction, in execution order. The lock is only held while the
+// history slices are collected, so slow consumers do not block new executions from being recorded.
+func (m *Manager) EachRecord(q HistoryQuery, fn func(ExecutionRecord) error) error {
+	return m.snapshot(q).each(fn)
+}
+
+// recordSnapshot references the history slices of a point in time. History slices are only ever
+// appended to or replaced as a whole, so the referenced records never change and need not be copied.
+type recordSnapshot struct {
+	query   HistoryQuery
+	names   []string
+	records map[string][]ExecutionRecord
+}
+
+func (m *Manager) snapshot(q HistoryQuery) *recordSnapshot {
+	m.refresh()
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	s := &recordSnapshot{query: q, records: make(map[string][]ExecutionRecord, len(m.history))}
+	for name, records := range m.history {
+		if q.Goction != "" && q.Goction != name {
+			continue
+		}
+		s.names = append(s.names, name)
+		s.records[name] = records
+	}
+	sort.Strings(s.names)
+	return s
+}
+
+func (s *recordSnapshot) each(fn func(ExecutionRecord) error) error {
+	for _, name := range s.names {
+		for _, record := range s.records[name] {
+			if !s.query.Matches(name, record) {
+				continue
+			}
+			if err := fn(record); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Export writes the records or per-goction statistics matching the query to w in the given format
+func (m *Manager) Export(w io.Writer, format, kind string, q HistoryQuery) error {
+	if err := ValidateExport(format, kind); err != nil {
+		return err
+	}
+
+	switch kind {
+	case "", KindHistory:
+		snapshot := m.snapshot(q)
+		if format == FormatJSONL {
+			encoder := json.NewEncoder(w)
+			return snapshot.each(func(record ExecutionRecord) error {
+				return encoder.Encode(NewExportRow(record))
+			})
+		}
+		each := func(fn func([]any) error) error {
+			return snapshot.each(func(record ExecutionRecord) error {
+				return fn(NewExportRow(record).values())
+			})
+		}
+		return writeTable(w, format, historyColumns, each)
+	case KindStats:
+		rows, err := m.aggregate(q)
+		if err != nil {
+			return err
+		}
+		if format == FormatJSONL {
+			encoder := json.NewEncoder(w)
+			for _, row := range rows {
+				if err := encoder.Encode(row); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		each := func(fn func([]any) error) error {
+			for _, row := range rows {
+				if err := fn(row.values()); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		return writeTable(w, format, statsColumns, each)
+	}
+	return nil
+}
+
+// ValidateExport checks that an export format and kind are supported
+func ValidateExport(format, kind string) error {
+	if !isExportFormat(format) {
+		return fmt.Errorf("unsupported export format %q (supported: %s)", format, strings.Join(ExportFormats, ", "))
+	}
+	if kind != "" && kind != KindHistory && kind != KindStats {
+		return fmt.Errorf("unsupported export kind %q (supported: %s, %s)", kind, KindHistory, KindStats)
+	}
+	return nil
+}
+
+// aggregate computes per-goction statistics over the records matching the query
+func (m *Manager) aggregate(q HistoryQuery) ([]StatsRow, error) {
+	var rows []StatsRow
+	index := make(map[string]int)
+	first := make(map[string]time.Time)
+	last := make(map[string]time.Time)
+
+	err := m.EachRecord(q, func(record ExecutionRecord) error {
+		i, ok := index[record.Goction]
+		if !ok {
+			i = len(rows)
+			index[record.Goction] = i
+			rows = append(rows, StatsRow{Goction: record.Goction})
+		}
+		row := &rows[i]
+		row.TotalCalls++
+		if record.Status == StatusSuccess {
+			row.SuccessfulCalls++
+		} else {
+			row.FailedCalls++
+		}
+		row.TotalDurationMs += float64(record.Duration) / float64(time.Millisecond)
+		if f, ok := first[record.Goction]; !ok || record.Timestamp.Before(f) {
+			first[record.Goction] = record.Timestamp
+		}
+		if l, ok := last[record.Goction]; !ok || record.Timestamp.After(l) {
+			last[record.Goction] = record.Timestamp
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range rows {
+		row := &rows[i]
+		row.SuccessRate = float64(row.SuccessfulCalls) / float64(row.TotalCalls) * 100
+		row.AvgDurationMs = row.TotalDurationMs / float64(row.TotalCalls)
+		row.FirstExecuted = first[row.Goction].Format(time.RFC3339Nano)
+		row.LastExecuted = last[row.Goction].Format(time.RFC3339Nano)
+	}
+	return rows, nil
+}
+
+func isExportFormat(format string) bool {
+	for _, f := range ExportFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// writeTable writes rows produced by each as CSV or as a columnar JSON document.
+// The columnar document is written one column at a time, calling each once per column,
+// so that rows never need to be held in memory:
+//
+//	{"format":"goction-columnar","version":1,"columns":[{"name":"id","values":[...]}, ...],"row_count":N}
+func writeTable(w io.Writer, format string, columns []string, each func(func([]any) error) error) error {
+	if format == FormatCSV {
+		writer := csv.NewWriter(w)
+		if err := writer.Write(columns); err != nil {
+			return err
+		}
+		record := make([]string, len(columns))
+		err := each(func(values []any) error {
+			for i, v := range values {
+				record[i] = csvValue(v)
+			}
+			return writer.Write(record)
+		})
+		if err != nil {
+			return err
+		}
+		writer.Flush()
+		return writer.Error()
+	}
+
+	if _, err := io.WriteString(w, `{"format":"goction-columnar","version":1,"columns":[`); err != nil {
+		return err
+	}
+	rowCount := 0
+	for i, column := range columns {
+		if i > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		name, _ := json.Marshal(column)
+		if _, err := fmt.Fprintf(w, `{"name":%s,"values":[`, name); err != nil {
+			return err
+		}
+		n := 0
+		err := each(func(values []any) error {
+			if n > 0 {
+				if _, err := io.WriteString(w, ","); err != nil {
+					return err
+				}
+			}
+			n++
+			data, err := json.Marshal(values[i])
+			if err != nil {
+				return err
+			}
+			_, err = w.Write(data)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(w, "]}"); err != nil {
+			return err
+		}
+		rowCount = n
+	}
+	_, err := fmt.Fprintf(w, "],\"row_count\":%d}\n", rowCount)
+	return err
+}
+
+func csvValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case []string:
+		if v == nil {
+			return ""
+		}
+		data, _ := json.Marshal(v)
+		return string(data)
+	default:
+		return fmt.Sprint(v)
+	}
+}
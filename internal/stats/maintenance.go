@@ -0,0 +1,310 @@
+package stats
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FormatStatsFile designates the internal stats file format, accepted by ReadRecords
+const FormatStatsFile = "stats"
+
+// Reset deletes the statistics and history of the given goctions, or of every goction if none is given
+func (m *Manager) Reset(names ...string) error {
+	return m.update(func() error {
+		if len(names) == 0 {
+			m.stats = make(map[string]*GoctionStats)
+			m.history = make(map[string][]ExecutionRecord)
+		}
+		for _, name := range names {
+			delete(m.stats, name)
+			delete(m.history, name)
+		}
+		return nil
+	})
+}
+
+// Prune deletes the statistics and history of every goction for which exists returns false
+// and returns the names of the deleted goctions
+func (m *Manager) Prune(exists func(name string) bool) ([]string, error) {
+	var removed []string
+	err := m.update(func() error {
+		seen := make(map[string]bool)
+		for name := range m.stats {
+			seen[name] = true
+		}
+		for name := range m.history {
+			seen[name] = true
+		}
+
+		for name := range seen {
+			if !exists(name) {
+				delete(m.stats, name)
+				delete(m.history, name)
+				removed = append(removed, name)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(removed)
+	return removed, nil
+}
+
+// Import merges execution records into the history, skipping records whose ID is already known,
+// and recomputes the statistics of the affected goctions. It returns the number of imported and skipped records.
+func (m *Manager) Import(records []ExecutionRecord) (int, int, error) {
+	imported, skipped := 0, 0
+	err := m.update(func() error {
+		known := make(map[string]bool)
+		for _, history := range m.history {
+			for _, record := range history {
+				known[record.ID] = true
+			}
+		}
+
+		added := make(map[string][]ExecutionRecord)
+		for _, record := range records {
+			if record.ID == "" || record.Goction == "" || record.Timestamp.IsZero() {
+				return fmt.Errorf("record %q is missing its ID, goction or timestamp", record.ID)
+			}
+			if known[record.ID] {
+				skipped++
+				continue
+			}
+			known[record.ID] = true
+			if record.Status == "" {
+				record.Status = StatusSuccess
+				if record.Error != "" {
+					record.Status = StatusFailure
+				}
+			}
+			added[record.Goction] = append(added[record.Goction], record)
+			imported++
+		}
+
+		// The merged history is a new slice: exports may still be reading the current one, see recordSnapshot
+		for name, records := range added {
+			history := make([]ExecutionRecord, 0, len(m.history[name])+len(records))
+			history = append(append(history, m.history[name]...), records...)
+			sort.SliceStable(history, func(i, j int) bool {
+				return history[i].Timestamp.Before(history[j].Timestamp)
+			})
+			m.history[name] = history
+			m.stats[name] = computeStats(history)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return imported, skipped, nil
+}
+
+func computeStats(history []ExecutionRecord) *GoctionStats {
+	stats := &GoctionStats{}
+	for _, record := range history {
+		stats.TotalCalls++
+		if record.Status == StatusSuccess {
+			stats.SuccessfulCalls++
+		}
+		stats.TotalDuration += record.Duration
+		if record.Timestamp.After(stats.LastExecuted) {
+			stats.LastExecuted = record.Timestamp
+		}
+	}
+	return stats
+}
+
+// DetectFormat guesses the format of an exported file from its extension
+func DetectFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV
+	case ".jsonl", ".ndjson":
+		return FormatJSONL
+	default:
+		return ""
+	}
+}
+
+// ReadRecords reads execution records exported as CSV, JSONL or columnar JSON, or stored in a stats file.
+// For JSON input, an empty format selects the columnar or stats file format from the document contents.
+func ReadRecords(r io.Reader, format string) ([]ExecutionRecord, error) {
+	switch format {
+	case FormatCSV:
+		return readCSV(r)
+	case FormatJSONL:
+		return readJSONL(r)
+	case "", FormatColumnar, FormatStatsFile:
+		return readJSONDocument(r, format)
+	default:
+		return nil, fmt.Errorf("unsupported import format %q", format)
+	}
+}
+
+func readJSONL(r io.Reader) ([]ExecutionRecord, error) {
+	var records []ExecutionRecord
+	decoder := json.NewDecoder(r)
+	for line := 1; ; line++ {
+		var row ExportRow
+		if err := decoder.Decode(&row); err == io.EOF {
+			return records, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("record %d: %w", line, err)
+		}
+		record, err := row.Record()
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", line, err)
+		}
+		records = append(records, record)
+	}
+}
+
+func readCSV(r io.Reader) ([]ExecutionRecord, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	var records []ExecutionRecord
+	for line := 2; ; line++ {
+		values, err := reader.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		columns := make(map[string]any, len(header))
+		for i, name := range header {
+			columns[name] = values[i]
+		}
+		row, err := rowFromColumns(columns)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		record, err := row.Record()
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		records = append(records, record)
+	}
+}
+
+func readJSONDocument(r io.Reader, format string) ([]ExecutionRecord, error) {
+	var doc struct {
+		Format  string `json:"format"`
+		Columns []struct {
+			Name   string `json:"name"`
+			Values []any  `json:"values"`
+		} `json:"columns"`
+		RowCount int                          `json:"row_count"`
+		History  map[string][]ExecutionRecord `json:"history"`
+	}
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode JSON: %w", err)
+	}
+
+	if format == FormatStatsFile || (format == "" && doc.Format == "") {
+		var records []ExecutionRecord
+		for name, history := range doc.History {
+			for _, record := range history {
+				if record.Goction == "" {
+					record.Goction = name
+				}
+				if record.ID == "" {
+					record.ID = legacyID(name, record.Timestamp)
+				}
+				records = append(records, record)
+			}
+		}
+		return records, nil
+	}
+
+	if doc.Format != "goction-columnar" {
+		return nil, fmt.Errorf("unsupported document format %q", doc.Format)
+	}
+
+	records := make([]ExecutionRecord, 0, doc.RowCount)
+	for i := 0; i < doc.RowCount; i++ {
+		columns := make(map[string]any, len(doc.Columns))
+		for _, column := range doc.Columns {
+			if i < len(column.Values) {
+				columns[column.Name] = column.Values[i]
+			}
+		}
+		row, err := rowFromColumns(columns)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", i+1, err)
+		}
+		record, err := row.Record()
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", i+1, err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// rowFromColumns builds an export row from column values, which are strings in CSV
+// and decoded JSON values in columnar documents
+func rowFromColumns(columns map[string]any) (ExportRow, error) {
+	str := func(name string) string {
+		if v, ok := columns[name].(string); ok {
+			return v
+		}
+		return ""
+	}
+
+	row := ExportRow{
+		ID:        str("id"),
+		Goction:   str("goction"),
+		Timestamp: str("timestamp"),
+		Status:    str("status"),
+		Result:    str("result"),
+		Error:     str("error"),
+		Caller:    str("caller"),
+		Trigger:   str("trigger"),
+		Host:      str("host"),
+		Version:   str("version"),
+	}
+
+	switch v := columns["duration_ms"].(type) {
+	case float64:
+		row.DurationMs = v
+	case string:
+		if v != "" {
+			d, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return row, fmt.Errorf("invalid duration_ms %q", v)
+			}
+			row.DurationMs = d
+		}
+	}
+
+	switch v := columns["args"].(type) {
+	case []any:
+		for _, arg := range v {
+			s, _ := arg.(string)
+			row.Args = append(row.Args, s)
+		}
+	case string:
+		if v != "" {
+			if err := json.Unmarshal([]byte(v), &row.Args); err != nil {
+				return row, fmt.Errorf("invalid args %q", v)
+			}
+		}
+	}
+
+	return row, nil
+}
@@ -0,0 +1,247 @@
+package stats
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultQueryLimit is the page size used when a query does not set one
+	DefaultQueryLimit = 100
+	// MaxQueryLimit is the largest page size a query may request
+	MaxQueryLimit = 1000
+)
+
+// HistoryQuery filters, sorts and paginates execution records
+type HistoryQuery struct {
+	Goction  string    // restrict to a single goction; empty means all goctions
+	Since    time.Time // inclusive lower bound on the timestamp
+	Until    time.Time // exclusive upper bound on the timestamp
+	Statuses []string  // accepted statuses; empty means any
+	Search   string    // case-insensitive substring searched in the result and error
+	Limit    int       // page size; 0 means DefaultQueryLimit
+	Cursor   string    // opaque cursor returned by a previous page
+	Order    string    // "desc" (newest first, default) or "asc"
+}
+
+// HistoryPage is one page of a history query
+type HistoryPage struct {
+	Records    []ExecutionRecord `json:"history"`
+	Total      int               `json:"total"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
+
+// Validate normalizes the query and checks its parameters
+func (q *HistoryQuery) Validate() error {
+	if q.Limit == 0 {
+		q.Limit = DefaultQueryLimit
+	}
+	if q.Limit < 0 || q.Limit > MaxQueryLimit {
+		return fmt.Errorf("limit must be between 1 and %d", MaxQueryLimit)
+	}
+
+	q.Order = strings.ToLower(q.Order)
+	if q.Order == "" {
+		q.Order = "desc"
+	}
+	if q.Order != "asc" && q.Order != "desc" {
+		return fmt.Errorf("order must be 'asc' or 'desc'")
+	}
+
+	if !q.Since.IsZero() && !q.Until.IsZero() && !q.Until.After(q.Since) {
+		return fmt.Errorf("until must be after since")
+	}
+
+	if _, err := decodeCursor(q.Cursor); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Matches reports whether a record satisfies the query filters, ignoring pagination
+func (q *HistoryQuery) Matches(name string, record ExecutionRecord) bool {
+	if q.Goction != "" && q.Goction != name {
+		return false
+	}
+	if !q.Since.IsZero() && record.Timestamp.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !record.Timestamp.Before(q.Until) {
+		return false
+	}
+	if len(q.Statuses) > 0 {
+		found := false
+		for _, status := range q.Statuses {
+			if strings.EqualFold(status, record.Status) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if q.Search != "" {
+		search := strings.ToLower(q.Search)
+		if !strings.Contains(strings.ToLower(record.Result), search) && !strings.Contains(strings.ToLower(record.Error), search) {
+			return false
+		}
+	}
+	return true
+}
+
+// QueryHistory returns the page of execution records selected by the query.
+// Records are ordered by timestamp then ID, and the cursor of a page is the key of its last record,
+// so executions recorded while paginating neither repeat nor skip records of the following pages.
+func (m *Manager) QueryHistory(q HistoryQuery) (HistoryPage, error) {
+	if err := q.Validate(); err != nil {
+		return HistoryPage{}, err
+	}
+	after, _ := decodeCursor(q.Cursor)
+
+	m.refresh()
+	m.mu.RLock()
+	var matched []ExecutionRecord
+	for name, records := range m.history {
+		if q.Goction != "" && q.Goction != name {
+			continue
+		}
+		for _, record := range records {
+			if q.Matches(name, record) {
+				matched = append(matched, record)
+			}
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(matched, func(i, j int) bool {
+		return q.before(keyOf(matched[i]), keyOf(matched[j]))
+	})
+
+	page := HistoryPage{Total: len(matched), Records: []ExecutionRecord{}}
+	start := 0
+	if after != nil {
+		start = sort.Search(len(matched), func(i int) bool {
+			return q.before(*after, keyOf(matched[i]))
+		})
+	}
+	if start >= len(matched) {
+		return page, nil
+	}
+
+	end := start + q.Limit
+	if end > len(matched) {
+		end = len(matched)
+	}
+	page.Records = matched[start:end]
+	if end < len(matched) {
+		page.NextCursor = encodeCursor(keyOf(matched[end-1]))
+	}
+
+	return page, nil
+}
+
+// recordKey is the position of a record in the history: its timestamp, then its ID for records sharing a timestamp
+type recordKey struct {
+	timestamp time.Time
+	id        string
+}
+
+func keyOf(record ExecutionRecord) recordKey {
+	return recordKey{timestamp: record.Timestamp, id: record.ID}
+}
+
+// before reports whether a comes before b in the order of the query
+func (q *HistoryQuery) before(a, b recordKey) bool {
+	if q.Order == "asc" {
+		return a.timestamp.Before(b.timestamp) || a.timestamp.Equal(b.timestamp) && a.id < b.id
+	}
+	return a.timestamp.After(b.timestamp) || a.timestamp.Equal(b.timestamp) && a.id > b.id
+}
+
+func encodeCursor(key recordKey) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(key.timestamp.UnixNano(), 10) + ":" + key.id))
+}
+
+// decodeCursor returns the key of the record a page starts after, nil for the first page
+func decodeCursor(cursor string) (*recordKey, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	nanos, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	ts, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	return &recordKey{timestamp: time.Unix(0, ts), id: id}, nil
+}
+
+// ParseTime parses an absolute (RFC 3339 or YYYY-MM-DD) or relative time such as "15m", "24h" or "7d",
+// which is interpreted as that long before now
+func ParseTime(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+
+	if strings.HasSuffix(value, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(value, "d"))
+		if err == nil && days >= 0 {
+			return now.Add(-time.Duration(days) * 24 * time.Hour), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid time %q: use RFC 3339, YYYY-MM-DD or a duration such as 24h or 7d", value)
+}
+
+// ParseStatuses splits a comma-separated status list
+func ParseStatuses(value string) []string {
+	var statuses []string
+	for _, status := range strings.Split(value, ",") {
+		if status = strings.TrimSpace(status); status != "" {
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses
+}
+
+// Percentile returns the p-th percentile (0-100) of durations using the nearest-rank method.
+// The slice is sorted in place.
+func Percentile(durations []time.Duration, p float64) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	rank := int(math.Ceil(p / 100 * float64(len(durations))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(durations) {
+		rank = len(durations)
+	}
+	return durations[rank-1]
+}
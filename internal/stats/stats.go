@@ -7,6 +7,10 @@ import (
 	"path/filepath"
 	"sync"
 	"time"
+
+	"goction/internal/filelock"
+
+	"github.com/google/uuid"
 )
 
 type GoctionStats struct {
@@ -16,89 +20,122 @@ type GoctionStats struct {
 	LastExecuted    time.Time     `json:"last_executed"`
 }
 
+// Execution statuses
+const (
+	StatusSuccess = "success"
+	StatusFailure = "failure"
+	StatusTimeout = "timeout"
+)
+
+// Execution triggers, set by the server from the way an execution was started
+const (
+	TriggerAPI       = "api"
+	TriggerCLI       = "cli"
+	TriggerAlert     = "alert"
+	TriggerDashboard = "dashboard"
+)
+
 type ExecutionRecord struct {
+	ID        string        `json:"id"`
+	Goction   string        `json:"goction"`
 	Timestamp time.Time     `json:"timestamp"`
 	Duration  time.Duration `json:"duration"`
 	Status    string        `json:"status"`
 	Result    string        `json:"result"`
+	Error     string        `json:"error,omitempty"`
+	Args      []string      `json:"args,omitempty"`
+	Caller    string        `json:"caller,omitempty"`
+	Trigger   string        `json:"trigger,omitempty"`
+	Host      string        `json:"host,omitempty"`
+	Version   string        `json:"version,omitempty"`
 }
 
+var hostname, _ = os.Hostname()
+
+// Manager holds the statistics and history stored in the stats file.
+// The server and CLI commands share the file: changes are made under a lock file, after reloading what other
+// processes wrote, and the file is replaced atomically so that readers never see it partly written.
 type Manager struct {
 	statsFile string
 	stats     map[string]*GoctionStats
 	history   map[string][]ExecutionRecord
 	mu        sync.RWMutex
+	modTime   time.Time // modification time of the stats file when last loaded or saved
 }
 
 func NewManager(statsFile string) (*Manager, error) {
-    dir := filepath.Dir(statsFile)
-    if err := os.MkdirAll(dir, 0775); err != nil {
-        return nil, fmt.Errorf("failed to create stats directory: %w", err)
-    }
-
-    m := &Manager{
-        statsFile: statsFile,
-        stats:     make(map[string]*GoctionStats),
-        history:   make(map[string][]ExecutionRecord),
-    }
-
-    // Check if the file exists and is not empty
-    if info, err := os.Stat(statsFile); err == nil && info.Size() > 0 {
-        if err := m.load(); err != nil {
-            return nil, fmt.Errorf("failed to load stats: %w", err)
-        }
-    } else {
-        // If the file doesn't exist or is empty, initialize it
-        if err := m.save(); err != nil {
-            return nil, fmt.Errorf("failed to initialize stats file: %w", err)
-        }
-    }
-
-    return m, nil
-}
-
-func (m *Manager) RecordExecution(name string, duration time.Duration, success bool, result string) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	if m.stats == nil {
-		m.stats = make(map[string]*GoctionStats)
-	}
-	if m.history == nil {
-		m.history = make(map[string][]ExecutionRecord)
+	dir := filepath.Dir(statsFile)
+	if err := os.MkdirAll(dir, 0775); err != nil {
+		return nil, fmt.Errorf("failed to create stats directory: %w", err)
 	}
 
-	stats, ok := m.stats[name]
-	if !ok {
-		stats = &GoctionStats{}
-		m.stats[name] = stats
+	m := &Manager{
+		statsFile: statsFile,
+		stats:     make(map[string]*GoctionStats),
+		history:   make(map[string][]ExecutionRecord),
 	}
 
-	stats.TotalCalls++
-	if success {
-		stats.SuccessfulCalls++
+	// Check if the file exists and is not empty
+	if info, err := os.Stat(statsFile); err == nil && info.Size() > 0 {
+		if err := m.load(); err != nil {
+			return nil, fmt.Errorf("failed to load stats: %w", err)
+		}
+	} else {
+		// If the file doesn't exist or is empty, initialize it
+		if err := m.update(func() error { return nil }); err != nil {
+			return nil, fmt.Errorf("failed to initialize stats file: %w", err)
+		}
 	}
-	stats.TotalDuration += duration
-	stats.LastExecuted = time.Now()
 
-	record := ExecutionRecord{
-		Timestamp: time.Now(),
-		Duration:  duration,
-		Status:    "success",
-		Result:    result,
+	return m, nil
+}
+
+// RecordExecution stores an execution record and updates the goction statistics.
+// Missing ID, timestamp, host and status are filled in; the completed record is returned.
+func (m *Manager) RecordExecution(record ExecutionRecord) ExecutionRecord {
+	if record.ID == "" {
+		record.ID = uuid.New().String()
+	}
+	if record.Timestamp.IsZero() {
+		record.Timestamp = time.Now()
 	}
-	if !success {
-		record.Status = "failure"
+	if record.Host == "" {
+		record.Host = hostname
 	}
+	if record.Status == "" {
+		record.Status = StatusSuccess
+		if record.Error != "" {
+			record.Status = StatusFailure
+		}
+	}
+
+	err := m.update(func() error {
+		name := record.Goction
+		stats, ok := m.stats[name]
+		if !ok {
+			stats = &GoctionStats{}
+			m.stats[name] = stats
+		}
 
-	m.history[name] = append(m.history[name], record)
+		stats.TotalCalls++
+		if record.Status == StatusSuccess {
+			stats.SuccessfulCalls++
+		}
+		stats.TotalDuration += record.Duration
+		stats.LastExecuted = record.Timestamp
 
-	if err := m.save(); err != nil {
+		m.history[name] = append(m.history[name], record)
+		return nil
+	})
+	if err != nil {
 		fmt.Printf("Failed to save stats: %v\n", err)
 	}
+
+	return record
 }
 
 func (m *Manager) GetStats(name string) (*GoctionStats, bool) {
+	m.refresh()
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -107,6 +144,7 @@ func (m *Manager) GetStats(name string) (*GoctionStats, bool) {
 }
 
 func (m *Manager) GetAllStats() map[string]*GoctionStats {
+	m.refresh()
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
@@ -124,12 +162,14 @@ func (m *Manager) GetAllStats() map[string]*GoctionStats {
 }
 
 func (m *Manager) GetExecutionHistory(name string) []ExecutionRecord {
+	m.refresh()
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
 	return m.history[name]
 }
 
+// load reads the stats file. The caller must hold the write lock or own the manager exclusively.
 func (m *Manager) load() error {
 	file, err := os.Open(m.statsFile)
 	if err != nil {
@@ -146,21 +186,43 @@ func (m *Manager) load() error {
 		return fmt.Errorf("failed to decode stats file: %w", err)
 	}
 
-	m.mu.Lock()
-	defer m.mu.Unlock()
+	if info, err := file.Stat(); err == nil {
+		m.modTime = info.ModTime()
+	}
 
 	m.stats = data.Stats
 	m.history = data.History
+	if m.stats == nil {
+		m.stats = make(map[string]*GoctionStats)
+	}
+	if m.history == nil {
+		m.history = make(map[string][]ExecutionRecord)
+	}
+
+	// Records written by older versions only carry the goction name as their map key and have no ID.
+	// Their ID is derived from their content so that it is stable across loads.
+	for name, records := range m.history {
+		for i := range records {
+			if records[i].Goction == "" {
+				records[i].Goction = name
+			}
+			if records[i].ID == "" {
+				records[i].ID = legacyID(name, records[i].Timestamp)
+			}
+		}
+	}
 
 	return nil
 }
 
+// save writes the stats file through a temporary file renamed into place.
+// The caller must hold the write lock and the lock file.
 func (m *Manager) save() error {
-	file, err := os.Create(m.statsFile)
+	file, err := os.CreateTemp(filepath.Dir(m.statsFile), "."+filepath.Base(m.statsFile)+".*")
 	if err != nil {
 		return fmt.Errorf("failed to create stats file: %w", err)
 	}
-	defer file.Close()
+	defer os.Remove(file.Name())
 
 	data := struct {
 		Stats   map[string]*GoctionStats     `json:"stats"`
@@ -173,13 +235,99 @@ func (m *Manager) save() error {
 	encoder := json.NewEncoder(file)
 	encoder.SetIndent("", "  ")
 	if err := encoder.Encode(data); err != nil {
+		file.Close()
 		return fmt.Errorf("failed to encode stats: %w", err)
 	}
+	if err := file.Chmod(0644); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write stats file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write stats file: %w", err)
+	}
+	if err := os.Rename(file.Name(), m.statsFile); err != nil {
+		return fmt.Errorf("failed to replace stats file: %w", err)
+	}
+
+	if info, err := os.Stat(m.statsFile); err == nil {
+		m.modTime = info.ModTime()
+	}
 
 	return nil
 }
 
+// update applies fn to the statistics and saves them. It holds the write lock and the lock file shared with
+// the other goction processes, and reloads the stats file first so that their changes are not overwritten.
+func (m *Manager) update(fn func() error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	unlock, err := filelock.Lock(m.statsFile + ".lock")
+	if err != nil {
+		return fmt.Errorf("failed to lock stats file: %w", err)
+	}
+	defer unlock()
+
+	m.reloadIfChanged()
+	if err := fn(); err != nil {
+		return err
+	}
+	return m.save()
+}
+
+// legacyID derives a stable ID for a record written before records had IDs
+func legacyID(name string, timestamp time.Time) string {
+	key := fmt.Sprintf("%s/%d", name, timestamp.UnixNano())
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(key)).String()
+}
+
+// reloadIfChanged reloads the stats file if another process (the CLI or the server) wrote it
+// since it was last loaded or saved. The caller must hold the write lock.
+func (m *Manager) reloadIfChanged() {
+	info, err := os.Stat(m.statsFile)
+	if err != nil || info.Size() == 0 || info.ModTime().Equal(m.modTime) {
+		return
+	}
+	if err := m.load(); err != nil {
+		fmt.Printf("Failed to reload stats: %v\n", err)
+	}
+}
+
+// refresh reloads the stats file before a read if another process wrote it, so that the server shows
+// the changes made by CLI commands such as stats reset and import
+func (m *Manager) refresh() {
+	info, err := os.Stat(m.statsFile)
+	if err != nil {
+		return
+	}
+	m.mu.RLock()
+	changed := !info.ModTime().Equal(m.modTime)
+	m.mu.RUnlock()
+	if changed {
+		m.mu.Lock()
+		m.reloadIfChanged()
+		m.mu.Unlock()
+	}
+}
+
+// GetExecution returns the record of the execution with the given ID
+func (m *Manager) GetExecution(id string) (ExecutionRecord, bool) {
+	m.refresh()
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, records := range m.history {
+		for _, record := range records {
+			if record.ID == id {
+				return record, true
+			}
+		}
+	}
+	return ExecutionRecord{}, false
+}
+
 func (m *Manager) GetAllHistory() map[string][]ExecutionRecord {
+	m.refresh()
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
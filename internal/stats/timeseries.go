@@ -0,0 +1,154 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// TimeRange is a period covered by a timeseries and the width of its buckets
+type TimeRange struct {
+	Span   time.Duration
+	Bucket time.Duration
+}
+
+// TimeRanges are the periods a timeseries can cover, by name
+var TimeRanges = map[string]TimeRange{
+	"24h": {Span: 24 * time.Hour, Bucket: time.Hour},
+	"7d":  {Span: 7 * 24 * time.Hour, Bucket: 6 * time.Hour},
+	"30d": {Span: 30 * 24 * time.Hour, Bucket: 24 * time.Hour},
+	"90d": {Span: 90 * 24 * time.Hour, Bucket: 24 * time.Hour},
+}
+
+// DefaultTimeRange is the range of a timeseries query that does not set one
+const DefaultTimeRange = "24h"
+
+// TimeseriesQuery selects the executions summarized by a timeseries
+type TimeseriesQuery struct {
+	Goction string // restrict to a single goction; empty means all goctions
+	Range   string // one of TimeRanges; empty means DefaultTimeRange
+	// UTCOffset is the offset of the time zone in which buckets are aligned and the heatmap is computed
+	UTCOffset time.Duration
+	// Now is the end of the covered period; zero means the current time
+	Now time.Time
+}
+
+// Bucket summarizes the executions of one interval
+type Bucket struct {
+	Start   time.Time `json:"start"`
+	Total   int       `json:"total"`
+	Success int       `json:"success"`
+	Failure int       `json:"failure"`
+	Timeout int       `json:"timeout"`
+	// Latency percentiles in milliseconds, zero for buckets without executions
+	P50Ms float64 `json:"p50_ms"`
+	P90Ms float64 `json:"p90_ms"`
+	P99Ms float64 `json:"p99_ms"`
+}
+
+// Timeseries summarizes the executions of a period in buckets, with a heatmap of when they ran
+type Timeseries struct {
+	Goction       string   `json:"goction,omitempty"`
+	Range         string   `json:"range"`
+	BucketSeconds int      `json:"bucket_seconds"`
+	Buckets       []Bucket `json:"buckets"`
+	// Summary covers the whole period; its Start is the start of the first bucket
+	Summary Bucket `json:"summary"`
+	// Heatmap counts executions by day of the week (0 is Sunday) and hour of the day, in the query time zone
+	Heatmap [7][24]int `json:"heatmap"`
+}
+
+// Timeseries summarizes the executions of the period selected by the query
+func (m *Manager) Timeseries(q TimeseriesQuery) (Timeseries, error) {
+	if q.Range == "" {
+		q.Range = DefaultTimeRange
+	}
+	r, ok := TimeRanges[q.Range]
+	if !ok {
+		return Timeseries{}, fmt.Errorf("unknown range %q (supported: %s)", q.Range, strings.Join(timeRangeNames(), ", "))
+	}
+	if q.UTCOffset < -14*time.Hour || q.UTCOffset > 14*time.Hour {
+		return Timeseries{}, fmt.Errorf("UTC offset must be between -14h and +14h")
+	}
+	if q.Now.IsZero() {
+		q.Now = time.Now()
+	}
+
+	// Buckets start on round times of the query time zone, the last one containing now
+	count := int(r.Span / r.Bucket)
+	last := q.Now.Add(q.UTCOffset).Truncate(r.Bucket).Add(-q.UTCOffset)
+	first := last.Add(-time.Duration(count-1) * r.Bucket)
+	zone := time.FixedZone("", int(q.UTCOffset/time.Second))
+
+	series := Timeseries{
+		Goction:       q.Goction,
+		Range:         q.Range,
+		BucketSeconds: int(r.Bucket / time.Second),
+		Buckets:       make([]Bucket, count),
+		Summary:       Bucket{Start: first},
+	}
+	durations := make([][]time.Duration, count)
+	var all []time.Duration
+	for i := range series.Buckets {
+		series.Buckets[i].Start = first.Add(time.Duration(i) * r.Bucket)
+	}
+
+	err := m.EachRecord(HistoryQuery{Goction: q.Goction, Since: first}, func(record ExecutionRecord) error {
+		i := int(record.Timestamp.Sub(first) / r.Bucket)
+		if i < 0 || i >= count {
+			return nil
+		}
+		series.Buckets[i].add(record)
+		series.Summary.add(record)
+		durations[i] = append(durations[i], record.Duration)
+		all = append(all, record.Duration)
+
+		local := record.Timestamp.In(zone)
+		series.Heatmap[local.Weekday()][local.Hour()]++
+		return nil
+	})
+	if err != nil {
+		return Timeseries{}, err
+	}
+
+	for i := range series.Buckets {
+		series.Buckets[i].setPercentiles(durations[i])
+	}
+	series.Summary.setPercentiles(all)
+	return series, nil
+}
+
+func (b *Bucket) add(record ExecutionRecord) {
+	b.Total++
+	switch record.Status {
+	case StatusSuccess:
+		b.Success++
+	case StatusTimeout:
+		b.Timeout++
+	default:
+		b.Failure++
+	}
+}
+
+func (b *Bucket) setPercentiles(durations []time.Duration) {
+	if len(durations) == 0 {
+		return
+	}
+	b.P50Ms = milliseconds(Percentile(durations, 50))
+	b.P90Ms = milliseconds(Percentile(durations, 90))
+	b.P99Ms = milliseconds(Percentile(durations, 99))
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func timeRangeNames() []string {
+	names := make([]string, 0, len(TimeRanges))
+	for name := range TimeRanges {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return TimeRanges[names[i]].Span < TimeRanges[names[j]].Span })
+	return names
+}
@@ -0,0 +1,117 @@
+// Package sysmetrics samples the CPU, memory and load of the host in the background,
+// so that pages showing them never wait for a measurement.
+package sysmetrics
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/host"
+	"github.com/shirou/gopsutil/v3/load"
+	"github.com/shirou/gopsutil/v3/mem"
+)
+
+// Sample is one measurement of the host
+type Sample struct {
+	Time time.Time `json:"time"`
+	// CPUPercent is the CPU usage of all cores since the previous sample
+	CPUPercent       float64 `json:"cpu_percent"`
+	MemoryPercent    float64 `json:"memory_percent"`
+	MemoryUsedBytes  uint64  `json:"memory_used_bytes"`
+	MemoryTotalBytes uint64  `json:"memory_total_bytes"`
+	Load1            float64 `json:"load1"`
+	UptimeSeconds    uint64  `json:"uptime_seconds"`
+}
+
+// Uptime returns the uptime of the host as a duration
+func (s Sample) Uptime() time.Duration {
+	return time.Duration(s.UptimeSeconds) * time.Second
+}
+
+// Collector samples the host at a fixed interval and hands the samples to its subscribers
+type Collector struct {
+	interval time.Duration
+	latest   atomic.Pointer[Sample]
+
+	mu          sync.Mutex
+	subscribers map[chan Sample]struct{}
+}
+
+// NewCollector creates a collector sampling every interval once Run is called
+func NewCollector(interval time.Duration) *Collector {
+	return &Collector{interval: interval, subscribers: make(map[chan Sample]struct{})}
+}
+
+// Run samples the host until stop is closed
+func (c *Collector) Run(stop <-chan struct{}) {
+	// The first CPU measurement covers the time since the process started
+	c.publish(collect())
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			c.publish(collect())
+		}
+	}
+}
+
+// Latest returns the last sample, or false before the first one
+func (c *Collector) Latest() (Sample, bool) {
+	if sample := c.latest.Load(); sample != nil {
+		return *sample, true
+	}
+	return Sample{}, false
+}
+
+// Subscribe returns a channel receiving every new sample, and the function to call to stop receiving them.
+// Samples are dropped for subscribers that are not ready to receive them.
+func (c *Collector) Subscribe() (<-chan Sample, func()) {
+	ch := make(chan Sample, 1)
+	c.mu.Lock()
+	c.subscribers[ch] = struct{}{}
+	c.mu.Unlock()
+	return ch, func() {
+		c.mu.Lock()
+		delete(c.subscribers, ch)
+		c.mu.Unlock()
+	}
+}
+
+func (c *Collector) publish(sample Sample) {
+	c.latest.Store(&sample)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for ch := range c.subscribers {
+		select {
+		case ch <- sample:
+		default:
+		}
+	}
+}
+
+// collect measures the host. Measurements that fail are left at zero.
+func collect() Sample {
+	sample := Sample{Time: time.Now()}
+	if percent, err := cpu.Percent(0, false); err == nil && len(percent) > 0 {
+		sample.CPUPercent = percent[0]
+	}
+	if memory, err := mem.VirtualMemory(); err == nil {
+		sample.MemoryPercent = memory.UsedPercent
+		sample.MemoryUsedBytes = memory.Used
+		sample.MemoryTotalBytes = memory.Total
+	}
+	if avg, err := load.Avg(); err == nil {
+		sample.Load1 = avg.Load1
+	}
+	if uptime, err := host.Uptime(); err == nil {
+		sample.UptimeSeconds = uptime
+	}
+	return sample
+}
@@ -2,27 +2,79 @@ package viewmodels
 
 import (
 	"goction/internal/config"
+	"goction/internal/execlog"
+	"goction/internal/goctions"
+	"goction/internal/manifest"
 	"goction/internal/stats"
-	"time"
+	"goction/internal/sysmetrics"
 )
 
-type SystemStats struct {
-	CPUUsage    float64
-	MemoryUsage float64
-	Uptime      time.Duration
+type DashboardData struct {
+	Config *config.Config
+	Stats  map[string]*stats.GoctionStats
+	// RecentExecutions lists the latest executions of every goction, newest first
+	RecentExecutions []stats.ExecutionRecord
+	// Goctions lists the installed goctions, sorted by name
+	Goctions []Goction
+	// GoctionsError is set when the goctions directory cannot be read, in which case Goctions is empty
+	GoctionsError string
+	// System is the latest sample of the system metrics, if HasSystem is set
+	System         sysmetrics.Sample
+	HasSystem      bool
+	GoctionVersion string
 }
 
-type DashboardData struct {
-	Config         *config.Config
-	Stats          map[string]*stats.GoctionStats
-	History        map[string][]stats.ExecutionRecord
-	RecentLogs     []string
+// Goction describes an installed goction and the arguments of its Run form
+type Goction struct {
+	Name        string
+	Description string
+	// Args are the declared arguments; a goction without declarations gets a free-form argument field
+	Args []manifest.Arg
+	// Error is set when the manifest cannot be read, in which case the goction cannot be run from the dashboard
+	Error string
+	// Disabled goctions cannot be run until they are enabled again
+	Disabled bool
+}
+
+// HistoryData is one page of the execution history of a goction
+type HistoryData struct {
+	Goction string
+	// Status is the status filter, empty for every status
+	Status  string
+	Records []stats.ExecutionRecord
+	Total   int
+	// Cursor is the cursor of the current page, empty for the newest executions
+	Cursor string
+	// NextCursor is the cursor of the page of older executions, empty on the last page
+	NextCursor     string
+	Error          string
 	GoctionVersion string
 }
 
-func Reverse(s []string) []string {
-	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
-		s[i], s[j] = s[j], s[i]
-	}
-	return s
+// ExecutionData describes one execution and its output
+type ExecutionData struct {
+	ExecutionID string
+	Record      stats.ExecutionRecord
+	// Found is false when no execution has this ID, in which case only the stored output may be shown
+	Found     bool
+	Lines     []execlog.Line
+	LogsError string
+	// RerunBlocked explains why the execution cannot be run again with the same arguments, if it cannot
+	RerunBlocked   string
+	GoctionVersion string
+}
+
+// EditorData is the source of a goction open in the editor
+type EditorData struct {
+	Goction     string
+	Description string
+	Files       []goctions.File
+	// Revisions lists the saved revisions, newest first
+	Revisions []goctions.Revision
+	// Args are the declared arguments of the test run form
+	Args []manifest.Arg
+	// PluginMode is set when goctions run inside the server, which keeps the first build it loaded
+	PluginMode     bool
+	Error          string
+	GoctionVersion string
 }
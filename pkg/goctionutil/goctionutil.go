@@ -20,7 +20,13 @@ func %s(args ...string) (string, error) {
 	// TODO: Implement your goction logic here
 	return fmt.Sprintf("Goction %s executed with args: %%s", strings.Join(args, ", ")), nil
 }
-`, TitleCase(name), TitleCase(name), name)
+`, SymbolName(name), SymbolName(name), name)
+}
+
+// SymbolName returns the function a goction plugin exports for the goction name: the name with its first letter
+// in upper case
+func SymbolName(name string) string {
+	return strings.Title(name)
 }
 
 // TitleCase converts a string to title case